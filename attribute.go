@@ -4,20 +4,27 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"reflect"
-	"strconv"
+	"time"
 )
 
-func fromMap(values map[string]interface{}) map[string]types.AttributeValue {
-	if values == nil {
-		return nil
+var numberType = reflect.TypeOf(Number(""))
+
+func fromMap(values map[string]interface{}) (map[string]types.AttributeValue, error) {
+	if len(values) == 0 {
+		return nil, nil
 	}
 
 	attributeValues := make(map[string]types.AttributeValue)
 	for k, v := range values {
-		attributeValues[k] = toAttributeValue(v)
+		attributeValue, err := toAttributeValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+
+		attributeValues[k] = attributeValue
 	}
 
-	return attributeValues
+	return attributeValues, nil
 }
 
 func constructItems(items []map[string]types.AttributeValue, to interface{}) ([]interface{}, error) {
@@ -34,36 +41,24 @@ func constructItems(items []map[string]types.AttributeValue, to interface{}) ([]
 	return outputItems, nil
 }
 
+// constructItem decodes the item into a new value of the same type as to.
 func constructItem(item map[string]types.AttributeValue, to interface{}) (interface{}, error) {
-	itemType := reflect.New(reflect.TypeOf(to))
-	itemValue := itemType.Elem()
-
-	for k, v := range item {
-		attribute, err := fromAttribute(v)
-		if err != nil {
-			return nil, err
-		}
-
-		itemValue.
-			FieldByName(k).
-			Set(reflect.ValueOf(attribute))
+	itemValue := reflect.New(reflect.TypeOf(to)).Elem()
+	if err := decodeValue(&types.AttributeValueMemberM{Value: item}, itemValue); err != nil {
+		return nil, err
 	}
 
 	return itemValue.Interface(), nil
 }
 
-func buildItem(item interface{}) map[string]types.AttributeValue {
-	itemValue := reflect.ValueOf(item)
-	itemType := reflect.TypeOf(item)
-	attributeValue := make(map[string]types.AttributeValue)
-
-	for i := 0; i < itemValue.NumField(); i++ {
-		value := toAttributeValue(itemValue.Field(i).Interface())
-
-		attributeValue[itemType.Field(i).Name] = value
+// buildItem encodes the item, which must be a struct or a pointer to one.
+func buildItem(item interface{}) (map[string]types.AttributeValue, error) {
+	itemValue := addressable(reflect.ValueOf(item))
+	if itemValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct item, got %T", item)
 	}
 
-	return attributeValue
+	return encodeStruct(itemValue)
 }
 
 func fromAttribute(attribute types.AttributeValue) (interface{}, error) {
@@ -71,24 +66,13 @@ func fromAttribute(attribute types.AttributeValue) (interface{}, error) {
 	case *types.AttributeValueMemberS:
 		return attribute.(*types.AttributeValueMemberS).Value, nil
 	case *types.AttributeValueMemberN:
-		return strconv.Atoi(attribute.(*types.AttributeValueMemberN).Value)
+		return parseNumber(attribute.(*types.AttributeValueMemberN).Value)
 	case *types.AttributeValueMemberB:
 		return attribute.(*types.AttributeValueMemberB).Value, nil
 	case *types.AttributeValueMemberSS:
 		return attribute.(*types.AttributeValueMemberSS).Value, nil
 	case *types.AttributeValueMemberNS:
-		var numbers []int
-
-		for _, v := range attribute.(*types.AttributeValueMemberNS).Value {
-			number, err := strconv.Atoi(v)
-			if err != nil {
-				return nil, err
-			}
-
-			numbers = append(numbers, number)
-		}
-
-		return numbers, nil
+		return numberSet(attribute.(*types.AttributeValueMemberNS).Value)
 	case *types.AttributeValueMemberBS:
 		return attribute.(*types.AttributeValueMemberBS).Value, nil
 	case *types.AttributeValueMemberM:
@@ -126,68 +110,384 @@ func fromAttribute(attribute types.AttributeValue) (interface{}, error) {
 	}
 }
 
-func toAttributeValue(value interface{}) types.AttributeValue {
-	switch value.(type) {
-	case string:
-		return &types.AttributeValueMemberS{Value: value.(string)}
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, complex64, complex128:
-		return &types.AttributeValueMemberN{Value: strconv.Itoa(value.(int))}
-	case []byte:
-		return &types.AttributeValueMemberB{Value: value.([]byte)}
-	case []string:
-		return &types.AttributeValueMemberSS{Value: value.([]string)}
-	case []int, []int8, []int16, []int32, []int64, []uint, []uint16, []uint32, []uint64, []float32, []float64, []complex64, []complex128:
-		var numbers []string
+// numberSet decodes a number set for an empty interface, which is an
+// []int when all of the numbers are integers that fit, an []float64
+// when they fit a float64 and an []Number otherwise.
+func numberSet(set []string) (interface{}, error) {
+	ints := make([]int, 0, len(set))
+	floats := make([]float64, 0, len(set))
+	numbers := make([]Number, 0, len(set))
+	for _, s := range set {
+		number, err := parseNumber(s)
+		if err != nil {
+			return nil, err
+		}
+
+		switch n := number.(type) {
+		case int:
+			ints = append(ints, n)
+			floats = append(floats, float64(n))
+		case float64:
+			floats = append(floats, n)
+		}
+
+		numbers = append(numbers, Number(s))
+	}
+
+	switch len(set) {
+	case len(ints):
+		return ints, nil
+	case len(floats):
+		return floats, nil
+	default:
+		return numbers, nil
+	}
+}
+
+func toAttributeValue(value interface{}) (types.AttributeValue, error) {
+	return encodeValue(addressable(reflect.ValueOf(value)))
+}
+
+// addressable dereferences pointers and copies values which are not
+// addressable, so that methods with pointer receivers are found.
+func addressable(value reflect.Value) reflect.Value {
+	value = reflect.Indirect(value)
+	if !value.IsValid() || value.CanAddr() {
+		return value
+	}
+
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+
+	return copied
+}
+
+// encodeValue encodes any supported Go value as an AttributeValue.
+//
+// Registered AttributeCodecs and AttributeMarshalers come first, in
+// that order. Times become RFC 3339 strings and durations become their
+// nanoseconds. After that, TextMarshalers become strings.
+//
+// Structs and maps with string keys become maps, where the fields of
+// structs are stored just like the fields of an item. Slices and arrays
+// become lists, apart from byte slices which become binaries, and
+// map[T]struct{} becomes a set of its keys. Nil pointers, interfaces,
+// slices and maps become NULL, unless they are fields tagged with
+// omitempty, which are left out.
+//
+// The encoder of each type is compiled once, see encoderFor.
+func encodeValue(value reflect.Value) (types.AttributeValue, error) {
+	if !value.IsValid() {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+
+	return encoderFor(value.Type())(value)
+}
+
+func encodeMap(value reflect.Value) (types.AttributeValue, error) {
+	values := make(map[string]types.AttributeValue, value.Len())
+	iter := value.MapRange()
+	for iter.Next() {
+		attributeValue, err := encodeValue(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", iter.Key().String(), err)
+		}
+
+		values[iter.Key().String()] = attributeValue
+	}
+
+	return &types.AttributeValueMemberM{Value: values}, nil
+}
+
+func encodeList(value reflect.Value) (types.AttributeValue, error) {
+	elemType := value.Type().Elem()
+	if elemType.Kind() == reflect.Uint8 {
+		bytes := make([]byte, value.Len())
+		reflect.Copy(reflect.ValueOf(bytes), value)
+
+		return &types.AttributeValueMemberB{Value: bytes}, nil
+	}
+
+	values := make([]types.AttributeValue, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		attributeValue, err := encodeValue(value.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		values = append(values, attributeValue)
+	}
+
+	return &types.AttributeValueMemberL{Value: values}, nil
+}
+
+// encodeStruct encodes the fields of the struct as the attributes
+// of an item.
+func encodeStruct(value reflect.Value) (map[string]types.AttributeValue, error) {
+	fields := structCodecFor(value.Type()).fields
+
+	attributeValues := make(map[string]types.AttributeValue, len(fields))
+	for _, field := range fields {
+		fieldValue, ok := fieldByIndex(value, field.index)
+		if !ok || field.omitEmpty && isEmpty(fieldValue) {
+			continue // Fields of nil embedded pointers are left out too
+		}
+
+		attributeValue, err := encodeField(field, fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.name, err)
+		}
+
+		attributeValues[field.name] = attributeValue
+	}
+
+	return attributeValues, nil
+}
+
+// isEmpty reports whether the value is left out by omitempty: nil
+// pointers, zero values and empty strings, slices and maps.
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	default:
+		return value.IsZero()
+	}
+}
+
+// decodeValue decodes the AttributeValue into the Go value, which must
+// be settable. This is the reverse of encodeValue, including the
+// AttributeCodecs and unmarshalers it comes with.
+//
+// Empty interfaces are given the values of fromAttribute and NULL
+// decodes into the zero value of any type.
+//
+// The decoder of each type is compiled once, see decoderFor.
+func decodeValue(attribute types.AttributeValue, value reflect.Value) error {
+	if _, ok := attribute.(*types.AttributeValueMemberNULL); ok {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	}
+
+	return decoderFor(value.Type())(attribute, value)
+}
+
+// decodeNumber parses the number into the exact type of the value,
+// failing when it does not fit.
+func decodeNumber(s string, value reflect.Value) error {
+	bits := value.Type().Bits()
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := parseInt(s, bits)
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := parseFloat(s, bits)
+		if err != nil {
+			return err
+		}
+
+		value.SetFloat(f)
+	default:
+		u, err := parseUint(s, bits)
+		if err != nil {
+			return err
+		}
+
+		value.SetUint(u)
+	}
+
+	return nil
+}
+
+func decodeMap(attribute types.AttributeValue, value reflect.Value) error {
+	if _, ok := attribute.(*types.AttributeValueMemberM); !ok && isSetMap(value.Type()) {
+		return decodeSet(attribute, value)
+	}
 
-		for _, i := range value.([]int) {
-			numbers = append(numbers, strconv.Itoa(i))
+	m, ok := attribute.(*types.AttributeValueMemberM)
+	if !ok || value.Type().Key().Kind() != reflect.String {
+		return decodeMismatch(attribute, value)
+	}
+
+	mapValue := reflect.MakeMapWithSize(value.Type(), len(m.Value))
+	for k, v := range m.Value {
+		elem := reflect.New(value.Type().Elem()).Elem()
+		if err := decodeValue(v, elem); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
 		}
 
-		return &types.AttributeValueMemberSS{Value: numbers}
-	case [][]byte:
-		return &types.AttributeValueMemberBS{Value: value.([][]byte)}
-	case map[string]interface{}:
-		mapValues := make(map[string]types.AttributeValue)
+		mapValue.SetMapIndex(reflect.ValueOf(k).Convert(value.Type().Key()), elem)
+	}
+
+	value.Set(mapValue)
+
+	return nil
+}
 
-		for k, v := range value.(map[string]interface{}) {
-			mapValues[k] = toAttributeValue(v)
+func decodeList(attribute types.AttributeValue, value reflect.Value) error {
+	var elems []types.AttributeValue
+	switch a := attribute.(type) {
+	case *types.AttributeValueMemberB:
+		if value.Type().Elem().Kind() != reflect.Uint8 || value.Kind() == reflect.Array && len(a.Value) > value.Len() {
+			return decodeMismatch(attribute, value)
 		}
 
-		return &types.AttributeValueMemberM{Value: mapValues}
-	case []interface{}:
-		var values []types.AttributeValue
+		if value.Kind() == reflect.Slice {
+			value.Set(reflect.MakeSlice(value.Type(), len(a.Value), len(a.Value)))
+		}
 
-		for _, v := range value.([]interface{}) {
-			values = append(values, toAttributeValue(v))
+		for i, b := range a.Value {
+			value.Index(i).SetUint(uint64(b))
 		}
 
-		return &types.AttributeValueMemberL{Value: values}
+		return nil
+	case *types.AttributeValueMemberSS:
+		for _, s := range a.Value {
+			elems = append(elems, &types.AttributeValueMemberS{Value: s})
+		}
+	case *types.AttributeValueMemberNS:
+		for _, n := range a.Value {
+			elems = append(elems, &types.AttributeValueMemberN{Value: n})
+		}
+	case *types.AttributeValueMemberBS:
+		for _, b := range a.Value {
+			elems = append(elems, &types.AttributeValueMemberB{Value: b})
+		}
+	case *types.AttributeValueMemberL:
+		elems = a.Value
 	default:
-		return &types.AttributeValueMemberBOOL{Value: value.(bool)}
-	}
-}
-
-func toAttributeType(value interface{}) (types.ScalarAttributeType, error) {
-	switch value.(type) {
-	case string:
-		return "S", nil
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, complex64, complex128:
-		return "N", nil
-	case []byte:
-		return "B", nil
-	case []string:
-		return "SS", nil
-	case []int, []int8, []int16, []int32, []int64, []uint, []uint16, []uint32, []uint64, []float32, []float64, []complex64, []complex128:
-		return "NS", nil
-	case [][]byte:
-		return "BS", nil
-	case map[string]interface{}, struct{}:
-		return "M", nil
-	case []interface{}:
-		return "L", nil
-	case bool:
-		return "BOOL", nil
+		return decodeMismatch(attribute, value)
+	}
+
+	list := value
+	if value.Kind() == reflect.Slice {
+		list = reflect.MakeSlice(value.Type(), len(elems), len(elems))
+	} else if len(elems) > value.Len() {
+		return fmt.Errorf("cannot decode %d elements into %v", len(elems), value.Type())
+	}
+
+	for i, elem := range elems {
+		if err := decodeValue(elem, list.Index(i)); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+
+	value.Set(list)
+
+	return nil
+}
+
+// decodeStruct decodes the attributes of an item into the fields of
+// the struct. Attributes without a field are ignored and fields without
+// an attribute are left untouched, so missing optional attributes leave
+// pointer fields nil.
+func decodeStruct(item map[string]types.AttributeValue, value reflect.Value) error {
+	byName := structCodecFor(value.Type()).byName
+	for k, v := range item {
+		field, ok := byName[k]
+		if !ok {
+			continue // Attributes outside the schema are ignored
+		}
+
+		fieldValue, err := settableFieldByIndex(value, field.index)
+		if err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+
+		if err := decodeField(*field, v, fieldValue); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+	}
+
+	return nil
+}
+
+// encodeField encodes the value of the struct field, honoring the
+// options of its tag.
+func encodeField(field schemaField, value reflect.Value) (types.AttributeValue, error) {
+	if field.timeEncoding == timeRFC3339 && !field.set {
+		return encodeValue(value)
+	}
+
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		}
+
+		value = value.Elem()
+	}
+
+	if field.set {
+		return encodeSet(value)
+	}
+
+	if value.Type() != timeType {
+		return nil, fmt.Errorf("unix and unixmilli only apply to %v, not %v", timeType, value.Type())
+	}
+
+	return encodeTime(value.Interface().(time.Time), field.timeEncoding), nil
+}
+
+// decodeField is the reverse of encodeField.
+func decodeField(field schemaField, attribute types.AttributeValue, value reflect.Value) error {
+	if _, ok := attribute.(*types.AttributeValueMemberNULL); ok || field.timeEncoding == timeRFC3339 {
+		return decodeValue(attribute, value)
+	}
+
+	if value.Kind() == reflect.Ptr {
+		value.Set(reflect.New(value.Type().Elem()))
+		value = value.Elem()
+	}
+
+	if value.Type() != timeType {
+		return fmt.Errorf("unix and unixmilli only apply to %v, not %v", timeType, value.Type())
+	}
+
+	t, err := decodeTime(attribute, field.timeEncoding)
+	if err != nil {
+		return err
+	}
+
+	value.Set(reflect.ValueOf(t))
+
+	return nil
+}
+
+func decodeMismatch(attribute types.AttributeValue, value reflect.Value) error {
+	return fmt.Errorf("cannot decode %T into %v", attribute, value.Type())
+}
+
+// toAttributeType is the type of key attribute which values of the
+// given type are encoded as in the field. Pointers are typed by what
+// they point to.
+func toAttributeType(field schemaField, valueType reflect.Type) (types.ScalarAttributeType, error) {
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+
+	zero := reflect.New(valueType).Elem()
+	if valueType.Kind() == reflect.Slice {
+		zero = reflect.MakeSlice(valueType, 0, 0) // Nil slices are NULL
+	}
+
+	attributeValue, err := encodeField(field, addressable(zero))
+	if err != nil {
+		return "", err
+	}
+
+	switch attributeValue.(type) {
+	case *types.AttributeValueMemberS:
+		return types.ScalarAttributeTypeS, nil
+	case *types.AttributeValueMemberN:
+		return types.ScalarAttributeTypeN, nil
+	case *types.AttributeValueMemberB:
+		return types.ScalarAttributeTypeB, nil
 	default:
-		return "", fmt.Errorf("unsupported type: %v", reflect.TypeOf(value))
+		return "", fmt.Errorf("unsupported key type: %v", valueType)
 	}
 }
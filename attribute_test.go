@@ -2,6 +2,7 @@ package dynago
 
 import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -18,6 +19,10 @@ func Test_fromAttribute(t *testing.T) {
 	}{
 		{"string", args{&types.AttributeValueMemberS{Value: "foo"}}, "foo", false},
 		{"number", args{&types.AttributeValueMemberN{Value: "123"}}, 123, false},
+		{"float", args{&types.AttributeValueMemberN{Value: "1.5"}}, 1.5, false},
+		{"big number", args{&types.AttributeValueMemberN{Value: "123456789012345678901234567890"}}, Number("123456789012345678901234567890"), false},
+		{"invalid number", args{&types.AttributeValueMemberN{Value: "abc"}}, nil, true},
+		{"floats", args{&types.AttributeValueMemberNS{Value: []string{"1", "1.5"}}}, []float64{1, 1.5}, false},
 		{"bytes", args{&types.AttributeValueMemberB{Value: []byte{1}}}, []byte{1}, false},
 		{"strings", args{&types.AttributeValueMemberSS{Value: []string{"foo", "bar"}}}, []string{"foo", "bar"}, false},
 		{"numbers", args{&types.AttributeValueMemberNS{Value: []string{"123", "456"}}}, []int{123, 456}, false},
@@ -40,3 +45,297 @@ func Test_fromAttribute(t *testing.T) {
 		})
 	}
 }
+
+func Test_buildItem(t *testing.T) {
+	type tagged struct {
+		Id    int    `dynago:"id,hash"`
+		Alias string `dynago:"alias,omitempty"`
+		Name  string
+	}
+
+	got, err := buildItem(tagged{Id: 1})
+	if err != nil {
+		t.Fatalf("buildItem() error = %v", err)
+	}
+
+	if _, ok := got["alias"]; ok {
+		t.Errorf("buildItem() = %v, want alias omitted", got)
+	}
+
+	if len(got) != 2 || got["id"] == nil || got["Name"] == nil {
+		t.Errorf("buildItem() = %v, want id and Name", got)
+	}
+}
+
+func Test_buildItem_nested(t *testing.T) {
+	type address struct {
+		Street string
+		Number int
+	}
+	type base struct {
+		Id int `dynago:"id,hash"`
+	}
+	type person struct {
+		base
+		Home      address
+		Previous  []address
+		Contacts  map[string]address
+		Nicknames []string
+	}
+
+	want := person{
+		base:      base{Id: 1},
+		Home:      address{"Main", 1},
+		Previous:  []address{{"Side", 2}},
+		Contacts:  map[string]address{"work": {"Office", 3}},
+		Nicknames: []string{"p"},
+	}
+
+	item, err := buildItem(want)
+	if err != nil {
+		t.Fatalf("buildItem() error = %v", err)
+	}
+
+	if _, ok := item["id"]; !ok {
+		t.Errorf("buildItem() = %v, want embedded id flattened", item)
+	}
+
+	home, ok := item["Home"].(*types.AttributeValueMemberM)
+	if !ok || home.Value["Street"] == nil {
+		t.Errorf("buildItem() Home = %v, want map", item["Home"])
+	}
+
+	got, err := constructItem(item, person{})
+	if err != nil {
+		t.Fatalf("constructItem() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("constructItem() = %v, want %v", got, want)
+	}
+}
+
+func Test_buildItem_embeddedPointer(t *testing.T) {
+	type Base struct {
+		Id int `dynago:"id,hash"`
+	}
+	type person struct {
+		*Base
+		Name string `dynago:"name,range"`
+	}
+
+	tests := []struct {
+		name     string
+		person   person
+		wantItem map[string]types.AttributeValue
+	}{
+		{"set", person{&Base{1}, "abc"}, testItem(map[string]interface{}{"id": 1, "name": "abc"})},
+		{"nil", person{nil, "abc"}, testItem(map[string]interface{}{"name": "abc"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, err := buildItem(tt.person)
+			if err != nil {
+				t.Fatalf("buildItem() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(item, tt.wantItem) {
+				t.Errorf("buildItem() = %v, want %v", item, tt.wantItem)
+			}
+
+			got, err := constructItem(item, person{})
+			if err != nil {
+				t.Fatalf("constructItem() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.person) {
+				t.Errorf("constructItem() = %v, want %v", got, tt.person)
+			}
+		})
+	}
+}
+
+func Test_constructItem_unexportedEmbeddedPointer(t *testing.T) {
+	type base struct {
+		Id int `dynago:"id,hash"`
+	}
+	type person struct {
+		*base
+		Name string `dynago:"name,range"`
+	}
+
+	item, err := buildItem(person{&base{1}, "abc"})
+	if err != nil {
+		t.Fatalf("buildItem() error = %v", err)
+	}
+
+	if want := testItem(map[string]interface{}{"id": 1, "name": "abc"}); !reflect.DeepEqual(item, want) {
+		t.Errorf("buildItem() = %v, want %v", item, want)
+	}
+
+	if _, err := constructItem(item, person{}); err == nil {
+		t.Errorf("constructItem() error = nil, want unexported embedded pointer")
+	}
+}
+
+func Test_buildItem_unsupported(t *testing.T) {
+	type invalid struct {
+		Id   int
+		Func func()
+	}
+
+	if _, err := buildItem(invalid{Id: 1, Func: func() {}}); err == nil {
+		t.Errorf("buildItem() error = nil, want unsupported type")
+	}
+}
+
+// testItem builds an item from values that are known to be encodable.
+func testItem(values map[string]interface{}) map[string]types.AttributeValue {
+	item, err := fromMap(values)
+	if err != nil {
+		panic(err)
+	}
+
+	return item
+}
+
+func Test_numbers(t *testing.T) {
+	type numbers struct {
+		Int     int
+		Int8    int8
+		Int64   int64
+		Uint8   uint8
+		Uint64  uint64
+		Float32 float32
+		Float64 float64
+		Number  Number
+		Numbers NumberSet
+	}
+
+	want := numbers{
+		Int:     -1,
+		Int8:    math.MinInt8,
+		Int64:   math.MaxInt64,
+		Uint8:   math.MaxUint8,
+		Uint64:  math.MaxUint64,
+		Float32: 1.25,
+		Float64: 0.1,
+		Number:  "12345678901234567890123456789012345678",
+		Numbers: NumberSet{"1", "2.5"},
+	}
+
+	item, err := buildItem(want)
+	if err != nil {
+		t.Fatalf("buildItem() error = %v", err)
+	}
+
+	if n := item["Uint64"].(*types.AttributeValueMemberN).Value; n != "18446744073709551615" {
+		t.Errorf("buildItem() Uint64 = %v, want 18446744073709551615", n)
+	}
+
+	if _, ok := item["Numbers"].(*types.AttributeValueMemberNS); !ok {
+		t.Errorf("buildItem() Numbers = %T, want number set", item["Numbers"])
+	}
+
+	got, err := constructItem(item, numbers{})
+	if err != nil {
+		t.Fatalf("constructItem() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("constructItem() = %v, want %v", got, want)
+	}
+}
+
+func Test_decodeNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		number  string
+		to      interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"uint8", "255", uint8(0), uint8(255), false},
+		{"uint8 overflow", "256", uint8(0), nil, true},
+		{"uint negative", "-1", uint(0), nil, true},
+		{"int8 underflow", "-129", int8(0), nil, true},
+		{"int exponent", "1E3", 0, 1000, false},
+		{"int fraction", "1.5", 0, nil, true},
+		{"float32", "1.5", float32(0), float32(1.5), false},
+		{"float32 overflow", "1E39", float32(0), nil, true},
+		{"number", "1E300", Number(""), Number("1E300"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := reflect.New(reflect.TypeOf(tt.to)).Elem()
+			err := decodeValue(&types.AttributeValueMemberN{Value: tt.number}, value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(value.Interface(), tt.want) {
+				t.Errorf("decodeValue() got = %v, want %v", value.Interface(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_encodeValue_invalidNumbers(t *testing.T) {
+	for _, value := range []interface{}{math.NaN(), math.Inf(1), Number("abc")} {
+		if _, err := toAttributeValue(value); err == nil {
+			t.Errorf("toAttributeValue(%v) error = nil, want error", value)
+		}
+	}
+}
+
+func Test_buildItem_pointers(t *testing.T) {
+	type optional struct {
+		Id       int `dynago:"id,hash"`
+		Nickname *string
+		Age      *int     `dynago:"age,omitempty"`
+		Tags     []string `dynago:"tags,omitempty"`
+	}
+
+	item, err := buildItem(optional{Id: 1, Tags: []string{}})
+	if err != nil {
+		t.Fatalf("buildItem() error = %v", err)
+	}
+
+	if _, ok := item["Nickname"].(*types.AttributeValueMemberNULL); !ok {
+		t.Errorf("buildItem() Nickname = %v, want NULL", item["Nickname"])
+	}
+
+	if len(item) != 2 {
+		t.Errorf("buildItem() = %v, want age and tags omitted", item)
+	}
+
+	nickname, age := "abc", 30
+	want := optional{Id: 1, Nickname: &nickname, Age: &age}
+
+	item, err = buildItem(want)
+	if err != nil {
+		t.Fatalf("buildItem() error = %v", err)
+	}
+
+	got, err := constructItem(item, optional{})
+	if err != nil {
+		t.Fatalf("constructItem() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("constructItem() = %v, want %v", got, want)
+	}
+
+	delete(item, "age")
+	item["Nickname"] = &types.AttributeValueMemberNULL{Value: true}
+
+	got, err = constructItem(item, optional{})
+	if err != nil {
+		t.Fatalf("constructItem() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, optional{Id: 1}) {
+		t.Errorf("constructItem() = %v, want nil pointers", got)
+	}
+}
@@ -0,0 +1,333 @@
+package dynago
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DynamoDb accepts at most 25 writes per BatchWriteItem.
+	maxBatchWriteSize = 25
+	// Unprocessed items are retried this many times before giving up.
+	maxBatchRetries = 8
+	// The first retry waits this long, with every retry after waiting twice as long.
+	batchBackoff = 50 * time.Millisecond
+)
+
+// batchSleep waits out the backoff between retries. Tests replace it to
+// record the backoff instead of waiting.
+var batchSleep = sleep
+
+// ErrUnprocessed is reported for batch writes which DynamoDb left
+// unprocessed, even after retrying.
+var ErrUnprocessed = errors.New("item was left unprocessed after retrying")
+
+// writeAll builds a write request for each of the items and writes
+// them in batches. The returned errors line up with the items, where
+// items whose request could not be built are never written.
+func (t Table) writeAll(ctx context.Context, items []interface{}, request func(item interface{}) (types.WriteRequest, error)) []error {
+	errs := make([]error, len(items))
+
+	var writes []types.WriteRequest
+	var indexes []int
+	for i, item := range items {
+		write, err := request(item)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		writes = append(writes, write)
+		indexes = append(indexes, i)
+	}
+
+	for i, err := range t.batchWrite(ctx, writes) {
+		errs[indexes[i]] = err
+	}
+
+	return errs
+}
+
+// batchWrite writes the requests to the Table in chunks, retrying any
+// unprocessed items with an exponential backoff. Chunks are written
+// concurrently according to Table.BatchConcurrency.
+//
+// The returned errors line up with the requests, where a nil error
+// means the write succeeded. Once the context is done, the remaining
+// chunks are not written and fail with the context's error.
+func (t Table) batchWrite(ctx context.Context, requests []types.WriteRequest) []error {
+	errs := make([]error, len(requests))
+
+	concurrency := t.BatchConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for start := 0; start < len(requests); start += maxBatchWriteSize {
+		end := start + maxBatchWriteSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			for i := start; i < end; i++ {
+				errs[i] = ctx.Err()
+			}
+
+			continue
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			t.writeChunk(ctx, requests[start:end], errs[start:end])
+		}(start, end)
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+func (t Table) writeChunk(ctx context.Context, requests []types.WriteRequest, errs []error) {
+	pending := make([]int, len(requests))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	fail := func(err error) {
+		for _, i := range pending {
+			errs[i] = err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			fail(err)
+			return
+		}
+
+		var writes []types.WriteRequest
+		for _, i := range pending {
+			writes = append(writes, requests[i])
+		}
+
+		output, err := t.db().BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{t.Name: writes},
+		})
+
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		unprocessed := output.UnprocessedItems[t.Name]
+		if len(unprocessed) == 0 {
+			return
+		}
+
+		pending = t.unprocessedIndexes(requests, pending, unprocessed)
+		if attempt == maxBatchRetries {
+			fail(ErrUnprocessed)
+			return
+		}
+
+		if err := batchSleep(ctx, batchBackoff<<attempt); err != nil {
+			fail(err)
+			return
+		}
+	}
+}
+
+// sleep waits for the duration, returning early with the context's
+// error when the context is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// unprocessedIndexes finds which of the pending requests were returned
+// as unprocessed by DynamoDb, matching them by the key of their item.
+// When an unprocessed request matches none of them, all of the pending
+// requests are kept, so that no write is taken as done without being
+// written.
+func (t Table) unprocessedIndexes(requests []types.WriteRequest, pending []int, unprocessed []types.WriteRequest) []int {
+	key := make(map[string]types.AttributeValue)
+	hashKey, rangeKey := t.keyNames()
+	for _, name := range []string{hashKey, rangeKey} {
+		if name != "" {
+			key[name] = nil
+		}
+	}
+
+	fingerprint := func(request types.WriteRequest) string {
+		item := writeItem(request)
+		if len(key) == 0 {
+			return keyFingerprint(item, item) // Without a key, the whole item has to match
+		}
+
+		return keyFingerprint(item, key)
+	}
+
+	byFingerprint := make(map[string]int, len(pending))
+	for _, i := range pending {
+		byFingerprint[fingerprint(requests[i])] = i
+	}
+
+	var indexes []int
+	for _, request := range unprocessed {
+		i, ok := byFingerprint[fingerprint(request)]
+		if !ok {
+			return pending
+		}
+
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	return indexes
+}
+
+// writeItem is the item a write request puts, or the key of the item it
+// deletes.
+func writeItem(request types.WriteRequest) map[string]types.AttributeValue {
+	if request.PutRequest != nil {
+		return request.PutRequest.Item
+	}
+
+	if request.DeleteRequest != nil {
+		return request.DeleteRequest.Key
+	}
+
+	return nil
+}
+
+// batchResult splits the items of a batch into the written items and
+// a *BatchWriteError describing the failed ones.
+func batchResult(items []interface{}, errs []error) ([]interface{}, error) {
+	var written []interface{}
+	var failures []BatchFailure
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, BatchFailure{i, items[i], err})
+			continue
+		}
+
+		written = append(written, items[i])
+	}
+
+	if len(failures) > 0 {
+		return written, &BatchWriteError{failures}
+	}
+
+	return written, nil
+}
+
+// DynamoDb accepts at most 100 keys per BatchGetItem.
+const maxBatchGetSize = 100
+
+// batchGet fetches the items for the keys in chunks, retrying any
+// unprocessed keys with an exponential backoff.
+//
+// The returned items line up with the keys, where a nil item means
+// there is no item for the key.
+func (t Table) batchGet(ctx context.Context, keys []map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	// DynamoDb rejects duplicate keys, so each key is only fetched once
+	var uniqueKeys []map[string]types.AttributeValue
+	indexes := make(map[string][]int)
+	for i, key := range keys {
+		fingerprint := keyFingerprint(key, key)
+		if _, ok := indexes[fingerprint]; !ok {
+			uniqueKeys = append(uniqueKeys, key)
+		}
+
+		indexes[fingerprint] = append(indexes[fingerprint], i)
+	}
+
+	b := newExprBuilder()
+	projection := b.projection(t.Projection)
+
+	items := make([]map[string]types.AttributeValue, len(keys))
+	for start := 0; start < len(uniqueKeys); start += maxBatchGetSize {
+		end := start + maxBatchGetSize
+		if end > len(uniqueKeys) {
+			end = len(uniqueKeys)
+		}
+
+		pending := uniqueKeys[start:end]
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt > maxBatchRetries {
+				return nil, ErrUnprocessed
+			}
+
+			if attempt > 0 {
+				if err := batchSleep(ctx, batchBackoff<<(attempt-1)); err != nil {
+					return nil, err
+				}
+			}
+
+			output, err := t.db().BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: map[string]types.KeysAndAttributes{t.Name: {
+					Keys:                     pending,
+					ProjectionExpression:     projection,
+					ExpressionAttributeNames: b.attributeNames(),
+				}},
+			})
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range output.Responses[t.Name] {
+				for _, i := range indexes[keyFingerprint(item, pending[0])] {
+					items[i] = item
+				}
+			}
+
+			pending = output.UnprocessedKeys[t.Name].Keys
+		}
+	}
+
+	return items, nil
+}
+
+// keyFingerprint identifies an item by the attributes which are
+// named in the key.
+func keyFingerprint(item map[string]types.AttributeValue, key map[string]types.AttributeValue) string {
+	var names []string
+	for name := range key {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var fingerprint strings.Builder
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&fingerprint, "%s=%T%v;", name, item[name], item[name])
+	}
+
+	return fingerprint.String()
+}
@@ -0,0 +1,289 @@
+package dynago
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_unprocessedIndexes(t *testing.T) {
+	requests := []types.WriteRequest{
+		{PutRequest: &types.PutRequest{Item: testItem(map[string]interface{}{"Id": 1, "Name": "a"})}},
+		{PutRequest: &types.PutRequest{Item: testItem(map[string]interface{}{"Id": 2, "Name": "b"})}},
+		{DeleteRequest: &types.DeleteRequest{Key: testItem(map[string]interface{}{"Id": 3})}},
+	}
+
+	tests := []struct {
+		name        string
+		table       Table
+		unprocessed []types.WriteRequest
+		want        []int
+	}{
+		{
+			"by key",
+			Table{Name: "Test", HashKey: "Id"},
+			[]types.WriteRequest{
+				{DeleteRequest: &types.DeleteRequest{Key: testItem(map[string]interface{}{"Id": 3})}},
+				{PutRequest: &types.PutRequest{Item: testItem(map[string]interface{}{"Id": 1, "Name": "changed"})}},
+			},
+			[]int{0, 2},
+		},
+		{
+			"by item without a key schema",
+			Table{Name: "Test"},
+			[]types.WriteRequest{
+				{PutRequest: &types.PutRequest{Item: testItem(map[string]interface{}{"Id": 2, "Name": "b"})}},
+			},
+			[]int{1},
+		},
+		{
+			"unmatched",
+			Table{Name: "Test", HashKey: "Id"},
+			[]types.WriteRequest{
+				{PutRequest: &types.PutRequest{Item: testItem(map[string]interface{}{"Id": 1})}},
+				{PutRequest: &types.PutRequest{Item: testItem(map[string]interface{}{"Id": 4})}},
+			},
+			[]int{0, 1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.table.unprocessedIndexes(requests, []int{0, 1, 2}, tt.unprocessed)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unprocessedIndexes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// unprocessedAPI leaves the last of the requests or keys of a batch
+// unprocessed, for the first few batches or, when times is negative, for
+// all of them.
+type unprocessedAPI struct {
+	API
+	times    int
+	requests [][]types.WriteRequest
+	keys     [][]map[string]types.AttributeValue
+}
+
+func (u *unprocessedAPI) BatchWriteItem(_ context.Context, input *dynamodb.BatchWriteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	writes := input.RequestItems["Test"]
+	u.requests = append(u.requests, writes)
+
+	output := &dynamodb.BatchWriteItemOutput{}
+	if u.times < 0 || len(u.requests) <= u.times {
+		last := writes[len(writes)-1]
+		output.UnprocessedItems = map[string][]types.WriteRequest{"Test": {
+			{PutRequest: &types.PutRequest{Item: copyTestItem(last.PutRequest.Item)}},
+		}}
+	}
+
+	return output, nil
+}
+
+// BatchGetItem responds with the keys themselves as the items.
+func (u *unprocessedAPI) BatchGetItem(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	keys := input.RequestItems["Test"].Keys
+	u.keys = append(u.keys, keys)
+
+	output := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{"Test": keys}}
+	if u.times < 0 || len(u.keys) <= u.times {
+		output.Responses["Test"] = keys[:len(keys)-1]
+		output.UnprocessedKeys = map[string]types.KeysAndAttributes{"Test": {
+			Keys: []map[string]types.AttributeValue{copyTestItem(keys[len(keys)-1])},
+		}}
+	}
+
+	return output, nil
+}
+
+// copyTestItem copies the item, the same as DynamoDb returns a copy of
+// the unprocessed requests.
+func copyTestItem(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	var values map[string]interface{}
+	if err := decodeValue(&types.AttributeValueMemberM{Value: item}, reflect.ValueOf(&values).Elem()); err != nil {
+		panic(err)
+	}
+
+	return testItem(values)
+}
+
+// recordBackoff replaces batchSleep for the test, recording the backoff
+// instead of waiting.
+func recordBackoff(t *testing.T) *[]time.Duration {
+	var backoff []time.Duration
+	batchSleep = func(_ context.Context, d time.Duration) error {
+		backoff = append(backoff, d)
+		return nil
+	}
+
+	t.Cleanup(func() { batchSleep = sleep })
+
+	return &backoff
+}
+
+func TestTable_PutAll_unprocessed(t *testing.T) {
+	items := []interface{}{struct{ Id int }{1}, struct{ Id int }{2}, struct{ Id int }{3}}
+
+	t.Run("retried", func(t *testing.T) {
+		backoff := recordBackoff(t)
+		api := &unprocessedAPI{times: 1}
+
+		written, err := NewClientWithAPI(api).newTable("Test", struct{ Id int }{}, nil).PutAll(items)
+		if err != nil || !reflect.DeepEqual(written, items) {
+			t.Errorf("PutAll() = %v, %v, want all items", written, err)
+		}
+
+		if len(api.requests) != 2 || len(api.requests[1]) != 1 {
+			t.Errorf("PutAll() made requests %v, want the last item retried once", api.requests)
+		}
+
+		if want := []time.Duration{batchBackoff}; !reflect.DeepEqual(*backoff, want) {
+			t.Errorf("PutAll() backoff = %v, want %v", *backoff, want)
+		}
+	})
+
+	t.Run("given up", func(t *testing.T) {
+		backoff := recordBackoff(t)
+		api := &unprocessedAPI{times: -1}
+
+		written, err := NewClientWithAPI(api).newTable("Test", struct{ Id int }{}, nil).PutAll(items)
+		if want := items[:2]; !reflect.DeepEqual(written, want) {
+			t.Errorf("PutAll() written = %v, want %v", written, want)
+		}
+
+		var batchErr *BatchWriteError
+		if !errors.As(err, &batchErr) || len(batchErr.Failures) != 1 || batchErr.Failures[0].Index != 2 ||
+			!errors.Is(batchErr.Failures[0].Err, ErrUnprocessed) {
+			t.Errorf("PutAll() err = %v, want the last item unprocessed", err)
+		}
+
+		if len(api.requests) != maxBatchRetries+1 {
+			t.Errorf("PutAll() made %d requests, want %d", len(api.requests), maxBatchRetries+1)
+		}
+
+		var want []time.Duration
+		for wait := batchBackoff; len(want) < maxBatchRetries; wait *= 2 {
+			want = append(want, wait)
+		}
+
+		if !reflect.DeepEqual(*backoff, want) {
+			t.Errorf("PutAll() backoff = %v, want %v", *backoff, want)
+		}
+	})
+}
+
+func Test_batchResult(t *testing.T) {
+	failed := errors.New("failed")
+
+	written, err := batchResult([]interface{}{"a", "b", "c"}, []error{nil, failed, nil})
+	if want := []interface{}{"a", "c"}; !reflect.DeepEqual(written, want) {
+		t.Errorf("batchResult() written = %v, want %v", written, want)
+	}
+
+	var batchErr *BatchWriteError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("batchResult() err = %v, want *BatchWriteError", err)
+	}
+
+	if want := []BatchFailure{{1, "b", failed}}; !reflect.DeepEqual(batchErr.Failures, want) {
+		t.Errorf("batchResult() failures = %v, want %v", batchErr.Failures, want)
+	}
+
+	if _, err := batchResult([]interface{}{"a"}, []error{nil}); err != nil {
+		t.Errorf("batchResult() err = %v, want nil", err)
+	}
+}
+
+func Test_keyFingerprint(t *testing.T) {
+	key := testItem(map[string]interface{}{"Id": 1, "Name": "abc"})
+	item := testItem(map[string]interface{}{"Name": "abc", "Id": 1, "Age": 30})
+	other := testItem(map[string]interface{}{"Name": "1", "Id": "abc"})
+
+	if keyFingerprint(item, key) != keyFingerprint(key, key) {
+		t.Errorf("keyFingerprint() differs for the item of a key")
+	}
+
+	if keyFingerprint(other, key) == keyFingerprint(key, key) {
+		t.Errorf("keyFingerprint() matches an item with differently typed attributes")
+	}
+}
+
+func TestTable_GetMany_unprocessed(t *testing.T) {
+	keys := []interface{}{struct{ Id int }{1}, struct{ Id int }{2}, struct{ Id int }{3}}
+
+	t.Run("retried", func(t *testing.T) {
+		backoff := recordBackoff(t)
+		api := &unprocessedAPI{times: 1}
+
+		items, err := NewClientWithAPI(api).newTable("Test", struct{ Id int }{}, nil).GetMany(keys)
+		if err != nil || !reflect.DeepEqual(items, keys) {
+			t.Errorf("GetMany() = %v, %v, want all items", items, err)
+		}
+
+		if len(api.keys) != 2 || len(api.keys[1]) != 1 {
+			t.Errorf("GetMany() requested %v, want the last key retried once", api.keys)
+		}
+
+		if want := []time.Duration{batchBackoff}; !reflect.DeepEqual(*backoff, want) {
+			t.Errorf("GetMany() backoff = %v, want %v", *backoff, want)
+		}
+	})
+
+	t.Run("given up", func(t *testing.T) {
+		backoff := recordBackoff(t)
+		api := &unprocessedAPI{times: -1}
+
+		_, err := NewClientWithAPI(api).newTable("Test", struct{ Id int }{}, nil).GetMany(keys)
+		if !errors.Is(err, ErrUnprocessed) {
+			t.Errorf("GetMany() err = %v, want %v", err, ErrUnprocessed)
+		}
+
+		if len(api.keys) != maxBatchRetries+1 {
+			t.Errorf("GetMany() made %d requests, want %d", len(api.keys), maxBatchRetries+1)
+		}
+
+		var want []time.Duration
+		for wait := batchBackoff; len(want) < maxBatchRetries; wait *= 2 {
+			want = append(want, wait)
+		}
+
+		if !reflect.DeepEqual(*backoff, want) {
+			t.Errorf("GetMany() backoff = %v, want %v", *backoff, want)
+		}
+	})
+}
+
+func Test_sleep(t *testing.T) {
+	if err := sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("sleep() err = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("sleep() err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func Test_batchWrite_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	requests := make([]types.WriteRequest, maxBatchWriteSize+1)
+	for i := range requests {
+		requests[i] = types.WriteRequest{PutRequest: &types.PutRequest{Item: testItem(map[string]interface{}{"Id": i})}}
+	}
+
+	for i, err := range (Table{Name: "Test", BatchConcurrency: 2}).batchWrite(ctx, requests) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("batchWrite()[%d] = %v, want %v", i, err, context.Canceled)
+		}
+	}
+}
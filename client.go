@@ -2,19 +2,101 @@ package dynago
 
 import (
 	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
 
+// Client makes the requests to DynamoDb. The Tables of a Client, which
+// are created with Client.NewTable and Client.CreateTable, make their
+// requests with it, so that one process can use several regions or
+// accounts at once.
+//
+//  client := dynago.NewClientFromConfig(cfg)
+//  table, err := client.NewTable("Person", Person{})
+//
+// The package-level functions, such as NewTable, use the default
+// Client, which is configured with UpdateOptions.
+type Client struct {
+	db API
+}
+
+// API is the part of the DynamoDb API which dynago uses, implemented
+// by *dynamodb.Client. Any other implementation, such as a fake or a
+// wrapper which records or retries requests, can be given to
+// NewClientWithAPI.
+//
+//  type logged struct{ dynago.API }
+//
+//  func (l logged) Query(ctx context.Context, input *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+//    log.Println("query", *input.TableName)
+//    return l.API.Query(ctx, input, optFns...)
+//  }
+type API interface {
+	DescribeTable(ctx context.Context, input *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	CreateTable(ctx context.Context, input *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	ListTables(ctx context.Context, input *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error)
+	GetItem(ctx context.Context, input *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	Query(ctx context.Context, input *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	Scan(ctx context.Context, input *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	PutItem(ctx context.Context, input *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, input *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, input *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	BatchGetItem(ctx context.Context, input *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+	BatchWriteItem(ctx context.Context, input *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	TransactGetItems(ctx context.Context, input *dynamodb.TransactGetItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactGetItemsOutput, error)
+	TransactWriteItems(ctx context.Context, input *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+}
+
+var _ API = (*dynamodb.Client)(nil)
+
+// NewClient creates a Client with the options of the DynamoDb client.
+func NewClient(options dynamodb.Options, optFns ...func(*dynamodb.Options)) *Client {
+	return &Client{dynamodb.New(options, optFns...)}
+}
+
+// NewClientFromConfig creates a Client from the AWS configuration, such
+// as one loaded by the config package of the SDK.
+func NewClientFromConfig(cfg aws.Config, optFns ...func(*dynamodb.Options)) *Client {
+	return &Client{dynamodb.NewFromConfig(cfg, optFns...)}
+}
+
+// NewClientWithAPI creates a Client which makes its requests with the
+// API instead of a DynamoDb client of its own.
+func NewClientWithAPI(api API) *Client { return &Client{api} }
+
+// UpdateOptions replaces the default Client with one using the options.
+// Tables created before keep making their requests with the Client they
+// were created with.
 func UpdateOptions(options dynamodb.Options) {
-	dbClient = dynamodb.New(options)
+	defaultClient = NewClient(options)
+}
+
+// UpdateAPI replaces the default Client with one making its requests
+// with the API. The same as for UpdateOptions, Tables created before
+// keep their Client.
+func UpdateAPI(api API) {
+	defaultClient = NewClientWithAPI(api)
 }
 
 var (
-	dbClient *dynamodb.Client
-	dbCtx    context.Context
+	defaultClient *Client
+	dbCtx         context.Context
 )
 
 func init() {
-	dbClient = dynamodb.New(dynamodb.Options{})
+	defaultClient = NewClient(dynamodb.Options{})
 	dbCtx = context.Background()
 }
+
+// db is the DynamoDb client of the Table.
+func (t Table) db() API { return t.dbClient().db }
+
+// dbClient is the Client of the Table, which is the default Client for
+// Tables which were constructed directly.
+func (t Table) dbClient() *Client {
+	if t.client != nil {
+		return t.client
+	}
+
+	return defaultClient
+}
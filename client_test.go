@@ -0,0 +1,125 @@
+package dynago
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"testing"
+)
+
+func TestTable_db(t *testing.T) {
+	client := NewClient(dynamodb.Options{Region: "eu-north-1"})
+	table := client.newTable("Test", struct{ Id int }{}, nil)
+
+	if table.db() != client.db {
+		t.Errorf("db() of a Client's Table is not the Client's")
+	}
+
+	if (Table{Name: "Test"}).db() != defaultClient.db {
+		t.Errorf("db() of a constructed Table is not the default Client's")
+	}
+
+	if txDB(nil) != defaultClient.db {
+		t.Errorf("txDB() of an empty transaction is not the default Client's")
+	}
+}
+
+func TestTx_client(t *testing.T) {
+	// Requests to the APIs panic, since they implement none of them
+	first := NewClientWithAPI(&pagedAPI{}).newTable("First", struct{ Id int }{}, nil)
+	second := NewClientWithAPI(&pagedAPI{}).newTable("Second", struct{ Id int }{}, nil)
+	key := struct{ Id int }{1}
+
+	tx := NewTx().ConditionCheck(first, key, All()).Delete(first, key, All())
+	if tx.err != nil || tx.client != first.client {
+		t.Errorf("Tx of one Client has Client %p and err = %v, want %p", tx.client, tx.err, first.client)
+	}
+
+	if err := tx.Delete(second, key, All()).Execute(); err == nil {
+		t.Errorf("Execute() of a Tx across Clients did not fail")
+	}
+
+	if _, err := (TransactGet{}).Get(first, key).Get(second, key).Execute(); err == nil {
+		t.Errorf("Execute() of a TransactGet across Clients did not fail")
+	}
+
+	if _, err := (TransactGet{}).Get(first, key).Get(&Table{Name: "Default"}, key).Execute(); err == nil {
+		t.Errorf("Execute() of a TransactGet with a Table of the default Client did not fail")
+	}
+}
+
+// pagedAPI serves Query and Scan from its pages, one page per request.
+type pagedAPI struct {
+	API
+	pages    [][]map[string]types.AttributeValue
+	requests int
+}
+
+func (p *pagedAPI) DescribeTable(_ context.Context, input *dynamodb.DescribeTableInput, _ ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	id := "Id"
+	return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+		TableName: input.TableName,
+		KeySchema: []types.KeySchemaElement{{AttributeName: &id, KeyType: types.KeyTypeHash}},
+	}}, nil
+}
+
+func (p *pagedAPI) Query(_ context.Context, input *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	items, lastKey := p.page(input.ExclusiveStartKey)
+	return &dynamodb.QueryOutput{Items: items, LastEvaluatedKey: lastKey}, nil
+}
+
+func (p *pagedAPI) Scan(_ context.Context, input *dynamodb.ScanInput, _ ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	items, lastKey := p.page(input.ExclusiveStartKey)
+	return &dynamodb.ScanOutput{Items: items, LastEvaluatedKey: lastKey}, nil
+}
+
+// page is the page after the LastEvaluatedKey of the previous one.
+func (p *pagedAPI) page(startKey map[string]types.AttributeValue) ([]map[string]types.AttributeValue, map[string]types.AttributeValue) {
+	page := 0
+	if startKey != nil {
+		_ = decodeValue(startKey["Page"], reflect.ValueOf(&page).Elem())
+	}
+
+	p.requests++
+	if page+1 < len(p.pages) {
+		return p.pages[page], testItem(map[string]interface{}{"Page": page + 1})
+	}
+
+	return p.pages[page], nil
+}
+
+func TestNewClientWithAPI(t *testing.T) {
+	api := &pagedAPI{pages: [][]map[string]types.AttributeValue{
+		{testItem(map[string]interface{}{"Id": 1})},
+		{testItem(map[string]interface{}{"Id": 2}), testItem(map[string]interface{}{"Id": 3})},
+	}}
+
+	table, err := NewClientWithAPI(api).NewTable("Test", struct{ Id int }{})
+	if err != nil {
+		t.Fatalf("NewTable() err = %v", err)
+	}
+
+	if table.HashKey != "Id" {
+		t.Errorf("NewTable() HashKey = %q, want %q", table.HashKey, "Id")
+	}
+
+	items, err := table.ScanAll()
+	if err != nil {
+		t.Fatalf("ScanAll() err = %v", err)
+	}
+
+	want := []interface{}{struct{ Id int }{1}, struct{ Id int }{2}, struct{ Id int }{3}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("ScanAll() = %v, want %v", items, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	api.requests = 0
+	if _, err := table.ScanAllCtx(ctx); !errors.Is(err, context.Canceled) || api.requests != 0 {
+		t.Errorf("ScanAllCtx() err = %v after %d requests, want %v", err, api.requests, context.Canceled)
+	}
+}
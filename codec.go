@@ -0,0 +1,373 @@
+package dynago
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// encoderFunc encodes a value of the type it was compiled for.
+// The value is valid and not nil.
+type encoderFunc func(value reflect.Value) (types.AttributeValue, error)
+
+// decoderFunc decodes an attribute other than NULL into a settable
+// value of the type it was compiled for.
+type decoderFunc func(attribute types.AttributeValue, value reflect.Value) error
+
+// structCodec is what is known about a struct type when encoding and
+// decoding it: its fields and the fields by attribute name.
+type structCodec struct {
+	fields []schemaField
+	byName map[string]*schemaField
+}
+
+// codecCache holds the encoders and decoders compiled for each Go type,
+// so that decoding thousands of items of a Query or Scan walks the
+// types only once.
+type codecCache struct {
+	encoders sync.Map // reflect.Type -> encoderFunc
+	decoders sync.Map // reflect.Type -> decoderFunc
+}
+
+var (
+	// compiled is the current *codecCache. It is replaced rather than
+	// emptied when an AttributeCodec is registered, so that a codec which
+	// was being compiled meanwhile is stored in the replaced cache only.
+	compiled     atomic.Value
+	structCodecs sync.Map // reflect.Type -> *structCodec
+)
+
+func init() {
+	compiled.Store(&codecCache{})
+}
+
+func encoderFor(t reflect.Type) encoderFunc {
+	cache := compiled.Load().(*codecCache)
+	if encode, ok := cache.encoders.Load(t); ok {
+		return encode.(encoderFunc)
+	}
+
+	encode, _ := cache.encoders.LoadOrStore(t, newEncoder(t))
+
+	return encode.(encoderFunc)
+}
+
+func decoderFor(t reflect.Type) decoderFunc {
+	cache := compiled.Load().(*codecCache)
+	if decode, ok := cache.decoders.Load(t); ok {
+		return decode.(decoderFunc)
+	}
+
+	decode, _ := cache.decoders.LoadOrStore(t, newDecoder(t))
+
+	return decode.(decoderFunc)
+}
+
+func structCodecFor(t reflect.Type) *structCodec {
+	if codec, ok := structCodecs.Load(t); ok {
+		return codec.(*structCodec)
+	}
+
+	fields := resolveFields(t)
+	codec := &structCodec{fields: fields, byName: make(map[string]*schemaField, len(fields))}
+	for i := range fields {
+		codec.byName[fields[i].name] = &fields[i]
+	}
+
+	cached, _ := structCodecs.LoadOrStore(t, codec)
+
+	return cached.(*structCodec)
+}
+
+// resetCodecs forgets the compiled encoders and decoders, which depend
+// on the registered AttributeCodecs. It is called after the codecs
+// change, so that encoders and decoders compiled from then on see them.
+func resetCodecs() {
+	compiled.Store(&codecCache{})
+}
+
+// newEncoder compiles the encoder of the type, following the rules
+// described by encodeValue.
+func newEncoder(t reflect.Type) encoderFunc {
+	encode := newKindEncoder(t)
+
+	if hasCodec(t, true) || hasMethod(t, attributeMarshalerType) || hasMethod(t, textMarshalerType) {
+		builtin := encode
+		encode = func(value reflect.Value) (types.AttributeValue, error) {
+			if attributeValue, ok, err := marshalCustom(value); ok {
+				return attributeValue, err
+			}
+
+			if t != timeType {
+				if attributeValue, ok, err := marshalText(value); ok {
+					return attributeValue, err
+				}
+			}
+
+			return builtin(value)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map:
+		notNil := encode
+		encode = func(value reflect.Value) (types.AttributeValue, error) {
+			if value.IsNil() {
+				return &types.AttributeValueMemberNULL{Value: true}, nil
+			}
+
+			return notNil(value)
+		}
+	}
+
+	return encode
+}
+
+func newKindEncoder(t reflect.Type) encoderFunc {
+	switch t {
+	case numberType:
+		return func(value reflect.Value) (types.AttributeValue, error) {
+			n, err := formatNumber(Number(value.String()))
+			if err != nil {
+				return nil, err
+			}
+
+			return &types.AttributeValueMemberN{Value: n}, nil
+		}
+	case timeType:
+		return func(value reflect.Value) (types.AttributeValue, error) {
+			return encodeTime(value.Interface().(time.Time), timeRFC3339), nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return func(value reflect.Value) (types.AttributeValue, error) { return encodeValue(value.Elem()) }
+	case reflect.String:
+		return func(value reflect.Value) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberS{Value: value.String()}, nil
+		}
+	case reflect.Bool:
+		return func(value reflect.Value) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberBOOL{Value: value.Bool()}, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(value reflect.Value) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberN{Value: strconv.FormatInt(value.Int(), 10)}, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(value reflect.Value) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberN{Value: strconv.FormatUint(value.Uint(), 10)}, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		bits := t.Bits()
+		return func(value reflect.Value) (types.AttributeValue, error) {
+			f := value.Float()
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, fmt.Errorf("unsupported number: %v", f)
+			}
+
+			return &types.AttributeValueMemberN{Value: strconv.FormatFloat(f, 'f', -1, bits)}, nil
+		}
+	case reflect.Slice, reflect.Array:
+		return encodeList
+	case reflect.Map:
+		if isSetMap(t) {
+			return encodeSet
+		}
+
+		if t.Key().Kind() != reflect.String {
+			return unsupportedEncoder(fmt.Errorf("unsupported map key type: %v", t.Key()))
+		}
+
+		return encodeMap
+	case reflect.Struct:
+		return func(value reflect.Value) (types.AttributeValue, error) {
+			values, err := encodeStruct(value)
+			if err != nil {
+				return nil, err
+			}
+
+			return &types.AttributeValueMemberM{Value: values}, nil
+		}
+	default:
+		return unsupportedEncoder(fmt.Errorf("unsupported type: %v", t))
+	}
+}
+
+func unsupportedEncoder(err error) encoderFunc {
+	return func(reflect.Value) (types.AttributeValue, error) { return nil, err }
+}
+
+// newDecoder compiles the decoder of the type, following the rules
+// described by decodeValue.
+func newDecoder(t reflect.Type) decoderFunc {
+	decode := newKindDecoder(t)
+
+	text := t != timeType && t != durationType && hasMethod(t, textUnmarshalerType)
+	if hasCodec(t, false) || hasMethod(t, attributeUnmarshalerType) || text {
+		builtin := decode
+		decode = func(attribute types.AttributeValue, value reflect.Value) error {
+			if ok, err := unmarshalCustom(attribute, value); ok {
+				return err
+			}
+
+			if text {
+				if ok, err := unmarshalText(attribute, value); ok {
+					return err
+				}
+			}
+
+			return builtin(attribute, value)
+		}
+	}
+
+	return decode
+}
+
+func newKindDecoder(t reflect.Type) decoderFunc {
+	switch t {
+	case numberType:
+		return func(attribute types.AttributeValue, value reflect.Value) error {
+			n, ok := attribute.(*types.AttributeValueMemberN)
+			if !ok {
+				return decodeMismatch(attribute, value)
+			}
+
+			value.SetString(n.Value)
+			return nil
+		}
+	case timeType:
+		return func(attribute types.AttributeValue, value reflect.Value) error {
+			decoded, err := decodeTime(attribute, timeRFC3339)
+			if err != nil {
+				return err
+			}
+
+			value.Set(reflect.ValueOf(decoded))
+			return nil
+		}
+	case durationType:
+		return func(attribute types.AttributeValue, value reflect.Value) error {
+			d, err := decodeDuration(attribute)
+			if err != nil {
+				return err
+			}
+
+			value.SetInt(int64(d))
+			return nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			return unsupportedDecoder(fmt.Errorf("unsupported type: %v", t))
+		}
+
+		return func(attribute types.AttributeValue, value reflect.Value) error {
+			decoded, err := fromAttribute(attribute)
+			if err != nil {
+				return err
+			}
+
+			value.Set(reflect.ValueOf(decoded))
+			return nil
+		}
+	case reflect.Ptr:
+		elemType := t.Elem()
+		return func(attribute types.AttributeValue, value reflect.Value) error {
+			elem := reflect.New(elemType)
+			if err := decodeValue(attribute, elem.Elem()); err != nil {
+				return err
+			}
+
+			value.Set(elem)
+			return nil
+		}
+	case reflect.String:
+		return func(attribute types.AttributeValue, value reflect.Value) error {
+			s, ok := attribute.(*types.AttributeValueMemberS)
+			if !ok {
+				return decodeMismatch(attribute, value)
+			}
+
+			value.SetString(s.Value)
+			return nil
+		}
+	case reflect.Bool:
+		return func(attribute types.AttributeValue, value reflect.Value) error {
+			b, ok := attribute.(*types.AttributeValueMemberBOOL)
+			if !ok {
+				return decodeMismatch(attribute, value)
+			}
+
+			value.SetBool(b.Value)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return func(attribute types.AttributeValue, value reflect.Value) error {
+			n, ok := attribute.(*types.AttributeValueMemberN)
+			if !ok {
+				return decodeMismatch(attribute, value)
+			}
+
+			if err := decodeNumber(n.Value, value); err != nil {
+				return fmt.Errorf("cannot decode into %v: %w", value.Type(), err)
+			}
+
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		return decodeList
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String && !isSetMap(t) {
+			return unsupportedDecoder(fmt.Errorf("unsupported map key type: %v", t.Key()))
+		}
+
+		return decodeMap
+	case reflect.Struct:
+		return func(attribute types.AttributeValue, value reflect.Value) error {
+			m, ok := attribute.(*types.AttributeValueMemberM)
+			if !ok {
+				return decodeMismatch(attribute, value)
+			}
+
+			return decodeStruct(m.Value, value)
+		}
+	default:
+		return unsupportedDecoder(fmt.Errorf("unsupported type: %v", t))
+	}
+}
+
+func unsupportedDecoder(err error) decoderFunc {
+	return func(types.AttributeValue, reflect.Value) error { return err }
+}
+
+// hasCodec reports whether an AttributeCodec is registered for the
+// type, which marshals values or else unmarshals them.
+func hasCodec(t reflect.Type, marshal bool) bool {
+	codec, ok := codecs.Load(t)
+	if !ok {
+		return false
+	}
+
+	if marshal {
+		return codec.(AttributeCodec).Marshal != nil
+	}
+
+	return codec.(AttributeCodec).Unmarshal != nil
+}
+
+// hasMethod reports whether values of the type, or pointers to them,
+// implement the interface.
+func hasMethod(t reflect.Type, iface reflect.Type) bool {
+	return t.Implements(iface) || t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(iface)
+}
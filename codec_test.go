@@ -0,0 +1,166 @@
+package dynago
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type benchAddress struct {
+	Street string
+	Number int
+}
+
+type benchPerson struct {
+	Id       string `dynago:"id,hash"`
+	Created  time.Time
+	Name     string
+	Age      uint8
+	Balance  float64
+	Active   bool
+	Nickname *string `dynago:",omitempty"`
+	Tags     []string
+	Home     benchAddress
+}
+
+// benchPage is a page of items, as returned by a Query or a Scan.
+func benchPage(b *testing.B, size int) []map[string]types.AttributeValue {
+	nickname := "abc"
+
+	page := make([]map[string]types.AttributeValue, size)
+	for i := range page {
+		item, err := buildItem(benchPerson{
+			Id:       fmt.Sprint(i),
+			Created:  time.Unix(int64(i), 0),
+			Name:     "Person",
+			Age:      uint8(i),
+			Balance:  float64(i) / 3,
+			Active:   i%2 == 0,
+			Nickname: &nickname,
+			Tags:     []string{"a", "b"},
+			Home:     benchAddress{"Main Street", i},
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		page[i] = item
+	}
+
+	return page
+}
+
+// BenchmarkConstructItems decodes a page of a Query or Scan.
+func BenchmarkConstructItems(b *testing.B) {
+	page := benchPage(b, 1000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := constructItems(page, benchPerson{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchTable is a Table whose Query and Scan page through ten pages of
+// a hundred items each.
+func benchTable(b *testing.B) *Table {
+	api := &pagedAPI{}
+	for i := 0; i < 10; i++ {
+		api.pages = append(api.pages, benchPage(b, 100))
+	}
+
+	return NewClientWithAPI(api).newTable("Person", benchPerson{}, nil)
+}
+
+func BenchmarkTable_Query(b *testing.B) {
+	table := benchTable(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := table.Query(Eq("id", S("1"))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTable_Scan(b *testing.B) {
+	table := benchTable(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := table.Scan(All()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBuildItem(b *testing.B) {
+	item := benchPerson{Id: "abc", Created: time.Now(), Name: "Person", Tags: []string{"a"}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := buildItem(item); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Test_encoderFor_cached(t *testing.T) {
+	first := reflect.ValueOf(encoderFor(reflect.TypeOf(benchPerson{}))).Pointer()
+	second := reflect.ValueOf(encoderFor(reflect.TypeOf(benchPerson{}))).Pointer()
+	if first != second {
+		t.Errorf("encoderFor() compiled the encoder twice")
+	}
+}
+
+func Test_RegisterCodec_recompiles(t *testing.T) {
+	type late struct{ Value string }
+
+	if _, err := toAttributeValue(late{"abc"}); err != nil {
+		t.Fatalf("toAttributeValue() error = %v", err)
+	}
+
+	RegisterCodec(late{}, AttributeCodec{
+		Marshal: func(value interface{}) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberS{Value: value.(late).Value}, nil
+		},
+	})
+
+	got, err := toAttributeValue(late{"abc"})
+	if err != nil {
+		t.Fatalf("toAttributeValue() error = %v", err)
+	}
+
+	if want := (&types.AttributeValueMemberS{Value: "abc"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("toAttributeValue() = %v, want %v", got, want)
+	}
+}
+
+func Test_RegisterCodec_concurrentCompile(t *testing.T) {
+	type racing struct{ Value string }
+
+	// An encoder which was being compiled while the codec was registered
+	// is stored only after the registration.
+	stale := compiled.Load().(*codecCache)
+	encode := newEncoder(reflect.TypeOf(racing{}))
+
+	RegisterCodec(racing{}, AttributeCodec{
+		Marshal: func(value interface{}) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberS{Value: value.(racing).Value}, nil
+		},
+	})
+
+	stale.encoders.LoadOrStore(reflect.TypeOf(racing{}), encode)
+
+	got, err := toAttributeValue(racing{"abc"})
+	if err != nil {
+		t.Fatalf("toAttributeValue() error = %v", err)
+	}
+
+	if want := (&types.AttributeValueMemberS{Value: "abc"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("toAttributeValue() = %v, want %v", got, want)
+	}
+}
@@ -1,5 +1,13 @@
 package dynago
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Condition is a magical (not really) way to create
 // expressions for DynamoDb. This is seen on countless different
 // operations and this way, it's a lot more safe.
@@ -7,11 +15,21 @@ package dynago
 //  // Finding a really old rabbit
 //  oldBoy, err := table.Query(dynago.Eq("Animal", dynago.S("Rabbit")).
 // 	  And(dynago.Gte("Age", dynago.N(20)))
+//
+// Conditions are combined from left to right, so mixing And and Or
+// never depends on operator precedence. Group and Not cover the rest.
+//
+//  // (Color = "Brown" or Color = "White") and not Age < 3
+//  dynago.Eq("Color", dynago.S("Brown")).
+//    Or(dynago.Eq("Color", dynago.S("White"))).
+//    And(dynago.Not(dynago.Lt("Age", dynago.N(3))))
 type Condition struct {
-	fieldName     string
+	path          AttributePath
 	values        []Value
 	conditionType conditionType
-	childClause   *conditionChildClause
+	size          bool
+	inner         *Condition
+	clauses       []conditionChildClause
 	options       *conditionOptions
 }
 
@@ -19,49 +37,102 @@ type conditionOptions struct {
 	limit *int32
 }
 
-// DynamoDb has a gigantic list of reserved keywords.
-// This is a super quick workaround for that. Enjoy.
-func (c Condition) qualifiedFieldName() string { return c.fieldName + "_expr" }
-
-func (c Condition) buildExpr() (*string, map[string]interface{}) {
+// buildExpr renders the Condition with the placeholders of the
+// exprBuilder. All() has no expression at all.
+func (c Condition) buildExpr(b *exprBuilder) *string {
 	if c.conditionType == all {
-		return nil, nil
+		return nil
 	}
 
-	values := make(map[string]interface{})
-	values[":"+c.qualifiedFieldName()] = c.rawValue()
-	currExpr := c.String()
+	expr := c.build(b)
+
+	return &expr
+}
+
+// build renders the whole Condition, including its clauses.
+func (c Condition) build(b *exprBuilder) string {
+	expr := c.buildTerm(b)
+
+	for i, clause := range c.clauses {
+		// Wrapping what came before keeps the Condition left to right
+		if i > 0 && clause.boolOp != c.clauses[i-1].boolOp {
+			expr = "(" + expr + ")"
+		}
 
-	if c.childClause == nil {
-		return &currExpr, values
+		expr += clause.opString() + clause.cond.buildOperand(b)
 	}
 
-	childExpr, childValues := c.childClause.cond.buildExpr()
-	for k, v := range childValues {
-		values[k] = v
+	return expr
+}
+
+// buildOperand renders the Condition so it can be used as an operand
+// of a boolean operator.
+func (c Condition) buildOperand(b *exprBuilder) string {
+	if len(c.clauses) > 0 {
+		return "(" + c.build(b) + ")"
 	}
 
-	expr := currExpr + c.childClause.opString() + *childExpr
-	return &expr, values
+	return c.build(b)
 }
 
-func (c Condition) rawValue() (rawValue interface{}) {
+// buildTerm renders the Condition without its clauses.
+func (c Condition) buildTerm(b *exprBuilder) string {
 	switch c.conditionType {
+	case not:
+		return "not " + c.inner.buildOperand(b)
+	case group:
+		return "(" + c.inner.build(b) + ")"
+	}
+
+	field := b.path(c.path)
+	if c.size {
+		field = "size(" + field + ")"
+	}
+
+	var values []string
+	for _, value := range c.values {
+		values = append(values, b.value(value.raw))
+	}
+
+	switch c.conditionType {
+	case eq:
+		return field + " = " + values[0]
+	case neq:
+		return field + " <> " + values[0]
+	case lt:
+		return field + " < " + values[0]
+	case lte:
+		return field + " <= " + values[0]
+	case gt:
+		return field + " > " + values[0]
+	case gte:
+		return field + " >= " + values[0]
 	case bt:
-		rawValue = []interface{}{}
-		for _, value := range c.values {
-			rawValue = append(rawValue.([]interface{}), value.raw)
-		}
+		return field + " between " + values[0] + " and " + values[1]
+	case beginsWith:
+		return "begins_with(" + field + ", " + values[0] + ")"
+	case contains:
+		return "contains(" + field + ", " + values[0] + ")"
+	case exists:
+		return "attribute_exists(" + field + ")"
+	case notExists:
+		return "attribute_not_exists(" + field + ")"
+	case isType:
+		return "attribute_type(" + field + ", " + values[0] + ")"
 	default:
-		rawValue = c.values[0].raw
+		return field + " in (" + strings.Join(values, ", ") + ")"
 	}
-
-	return
 }
 
-func (c Condition) And(condition Condition) Condition {
-	c.childClause = &conditionChildClause{and, condition}
-	c.childClause.cond.options = c.options
+// And requires both this and the given Condition to be met.
+func (c Condition) And(condition Condition) Condition { return c.with(and, condition) }
+
+// Or requires either this or the given Condition to be met.
+func (c Condition) Or(condition Condition) Condition { return c.with(or, condition) }
+
+func (c Condition) with(op boolOp, condition Condition) Condition {
+	condition.options = c.options
+	c.clauses = append(c.clauses[:len(c.clauses):len(c.clauses)], conditionChildClause{op, condition})
 
 	return c
 }
@@ -72,41 +143,171 @@ func (c Condition) WithLimit(limit int32) Condition {
 	return c
 }
 
-func (c Condition) String() string {
-	name := c.qualifiedFieldName()
+// splitKey splits the Condition into a key condition and a filter, so
+// it can be used to query a table with the given key attributes.
+//
+// Only Conditions joined by And can be split. The key condition is an
+// Eq on the hash key, along with one comparison, Bt or BeginsWith on the
+// range key. Everything else becomes the filter, which is All() when
+// there is nothing left to filter on.
+func (c Condition) splitKey(hashKey, rangeKey string) (key Condition, filter Condition, err error) {
+	terms := []Condition{c}
+	terms[0].clauses = nil
+	for _, clause := range c.clauses {
+		if clause.boolOp != and {
+			return key, filter, errors.New("query condition must be joined by And to find its key condition")
+		}
+
+		terms = append(terms, clause.cond)
+	}
+
+	var hashTerm, rangeTerm *Condition
+	var filters []Condition
+	for i, term := range terms {
+		switch {
+		case hashTerm == nil && term.isKeyTerm(hashKey) && term.conditionType == eq:
+			hashTerm = &terms[i]
+		case rangeTerm == nil && rangeKey != "" && term.isKeyTerm(rangeKey):
+			rangeTerm = &terms[i]
+		default:
+			filters = append(filters, term)
+		}
+	}
+
+	if hashTerm == nil {
+		return key, filter, fmt.Errorf("query condition must contain Eq on hash key %s", hashKey)
+	}
+
+	key = *hashTerm
+	if rangeTerm != nil {
+		key = key.And(*rangeTerm)
+	}
+
+	filter = All()
+	for i, term := range filters {
+		if i == 0 {
+			filter = term
+			continue
+		}
+
+		filter = filter.And(term)
+	}
+
+	key.options = c.options
+
+	return key, filter, nil
+}
+
+// isKeyTerm tells whether the Condition alone can be used in a key
+// condition on the given key.
+func (c Condition) isKeyTerm(keyName string) bool {
+	if c.path != Path(keyName) || c.size || len(c.clauses) > 0 {
+		return false
+	}
+
 	switch c.conditionType {
-	case eq:
-		return c.fieldName + " = :" + name
-	case neq:
-		return c.fieldName + " != :" + name
-	case lt:
-		return c.fieldName + " < :" + name
-	case lte:
-		return c.fieldName + " <= :" + name
-	case gt:
-		return c.fieldName + " > :" + name
-	case gte:
-		return c.fieldName + " >= :" + name
-	case bt:
-		return c.fieldName + " between :" + name + "_lower and :" + name + "_upper"
+	case eq, lt, lte, gt, gte, bt, beginsWith:
+		return true
 	default:
-		return "" // Special cases
+		return false
 	}
 }
 
-func All() Condition                              { return Condition{conditionType: all, options: new(conditionOptions)} }
-func Eq(fieldName string, value Value) Condition  { return newCond(fieldName, []Value{value}, eq) }
-func Neq(fieldName string, value Value) Condition { return newCond(fieldName, []Value{value}, neq) }
-func Lt(fieldName string, value Value) Condition  { return newCond(fieldName, []Value{value}, lt) }
-func Lte(fieldName string, value Value) Condition { return newCond(fieldName, []Value{value}, lte) }
-func Gt(fieldName string, value Value) Condition  { return newCond(fieldName, []Value{value}, gt) }
-func Gte(fieldName string, value Value) Condition { return newCond(fieldName, []Value{value}, gte) }
-func Bt(fieldName string, lower, upper Value) Condition {
-	return newCond(fieldName, []Value{lower, upper}, bt)
+// String renders the Condition as a DynamoDb expression, where
+// names and values are replaced by placeholders.
+func (c Condition) String() string {
+	expr := c.buildExpr(newExprBuilder())
+	if expr == nil {
+		return ""
+	}
+
+	return *expr
+}
+
+func All() Condition                                { return Condition{conditionType: all, options: new(conditionOptions)} }
+func Eq[P pathLike](path P, value Value) Condition  { return newCond(path, []Value{value}, eq) }
+func Neq[P pathLike](path P, value Value) Condition { return newCond(path, []Value{value}, neq) }
+func Lt[P pathLike](path P, value Value) Condition  { return newCond(path, []Value{value}, lt) }
+func Lte[P pathLike](path P, value Value) Condition { return newCond(path, []Value{value}, lte) }
+func Gt[P pathLike](path P, value Value) Condition  { return newCond(path, []Value{value}, gt) }
+func Gte[P pathLike](path P, value Value) Condition { return newCond(path, []Value{value}, gte) }
+func Bt[P pathLike](path P, lower, upper Value) Condition {
+	return newCond(path, []Value{lower, upper}, bt)
+}
+
+// BeginsWith requires a string field to start with the given prefix.
+// This is also how range keys are queried by prefix.
+func BeginsWith[P pathLike](path P, prefix string) Condition {
+	return newCond(path, []Value{S(prefix)}, beginsWith)
+}
+
+// Contains requires a string field to contain the given substring, or a
+// set or list field to contain the given element.
+func Contains[P pathLike](path P, value Value) Condition {
+	return newCond(path, []Value{value}, contains)
+}
+
+// Exists requires the field to be present in the item.
+func Exists[P pathLike](path P) Condition { return newCond(path, nil, exists) }
+
+// NotExists requires the field to be absent from the item.
+// Using it on the hash key makes for a put which never overwrites.
+//
+//  _, err := table.PutWithCondition(dynago.NotExists("Id"), item)
+func NotExists[P pathLike](path P) Condition { return newCond(path, nil, notExists) }
+
+// AttributeType requires the field to be of the given DynamoDb type, which
+// is one of S, SS, N, NS, B, BS, BOOL, NULL, L or M.
+func AttributeType[P pathLike](path P, attributeType string) Condition {
+	return newCond(path, []Value{S(attributeType)}, isType)
+}
+
+// In requires the field to be equal to any of the given values.
+// At least one value is required, as DynamoDb rejects an empty list.
+func In[P pathLike](path P, value Value, values ...Value) Condition {
+	return newCond(path, append([]Value{value}, values...), in)
+}
+
+// Size compares the size of a field rather than the field itself.
+// That is the length of a string or binary, or the number of elements
+// in a set, list or map.
+//
+//  dynago.Size("Tags").Gt(dynago.N(3))
+func Size[P pathLike](path P) SizeCondition { return SizeCondition{AttributePath(path)} }
+
+// SizeCondition creates Conditions on the size of a field.
+// See Size for more.
+type SizeCondition struct{ path AttributePath }
+
+func (s SizeCondition) Eq(value Value) Condition  { return sized(Eq(s.path, value)) }
+func (s SizeCondition) Neq(value Value) Condition { return sized(Neq(s.path, value)) }
+func (s SizeCondition) Lt(value Value) Condition  { return sized(Lt(s.path, value)) }
+func (s SizeCondition) Lte(value Value) Condition { return sized(Lte(s.path, value)) }
+func (s SizeCondition) Gt(value Value) Condition  { return sized(Gt(s.path, value)) }
+func (s SizeCondition) Gte(value Value) Condition { return sized(Gte(s.path, value)) }
+func (s SizeCondition) Bt(lower, upper Value) Condition {
+	return sized(Bt(s.path, lower, upper))
 }
 
-func newCond(fieldName string, values []Value, ct conditionType) Condition {
-	return Condition{fieldName, values, ct, nil, new(conditionOptions)}
+func sized(c Condition) Condition {
+	c.size = true
+
+	return c
+}
+
+// Not requires the given Condition not to be met.
+func Not(condition Condition) Condition {
+	return Condition{conditionType: not, inner: &condition, options: new(conditionOptions)}
+}
+
+// Group wraps the given Condition in parentheses, so it is
+// evaluated on its own before being combined with anything else.
+func Group(condition Condition) Condition {
+	return Condition{conditionType: group, inner: &condition, options: new(conditionOptions)}
+}
+
+func newCond[P pathLike](path P, values []Value, ct conditionType) Condition {
+	return Condition{path: AttributePath(path), values: values, conditionType: ct, options: new(conditionOptions)}
 }
 
 type conditionType uint8
@@ -119,7 +320,15 @@ const (
 	gt
 	gte
 	bt
+	beginsWith
+	contains
+	exists
+	notExists
+	isType
+	in
 	all
+	not
+	group
 )
 
 type conditionChildClause struct {
@@ -132,7 +341,7 @@ func (c conditionChildClause) opString() string {
 	case and:
 		return " and "
 	default:
-		return "" // Future cases
+		return " or "
 	}
 }
 
@@ -140,16 +349,31 @@ type boolOp uint8
 
 const (
 	and boolOp = iota
+	or
 )
 
 type Value struct{ raw interface{} }
 
 func S(value string) Value                 { return Value{value} }
 func N(value int) Value                    { return Value{value} }
+func F(value float64) Value                { return Value{value} }
+func BigN(value Number) Value              { return Value{value} }
 func BOOL(value bool) Value                { return Value{value} }
 func B(value []byte) Value                 { return Value{value} }
-func SS(value []string) Value              { return Value{value} }
-func BS(value [][]byte) Value              { return Value{value} }
-func NS(value []int) Value                 { return Value{value} }
+func SS(value []string) Value              { return Value{StringSet(value)} }
+func BS(value [][]byte) Value              { return Value{BinarySet(value)} }
 func M(value map[string]interface{}) Value { return Value{value} }
 func L(value []interface{}) Value          { return Value{value} }
+
+func NS(value []int) Value {
+	set := make(NumberSet, len(value))
+	for i, n := range value {
+		set[i] = Number(strconv.Itoa(n))
+	}
+
+	return Value{set}
+}
+
+// Time is a time.Time value, stored the same way as time.Time fields
+// without the unix or unixmilli options.
+func Time(value time.Time) Value { return Value{value} }
@@ -0,0 +1,165 @@
+package dynago
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCondition_String(t *testing.T) {
+	name := "A"
+
+	tests := []struct {
+		name      string
+		condition Condition
+		want      string
+	}{
+		{"all", All(), ""},
+		{"single", Eq("A", N(1)), "#n0 = :v0"},
+		{"not equal", Neq("A", N(1)), "#n0 <> :v0"},
+		{"between", Bt("A", N(1), N(2)), "#n0 between :v0 and :v1"},
+		{"begins with", BeginsWith("A", "abc"), "begins_with(#n0, :v0)"},
+		{"contains", Contains("A", S("abc")), "contains(#n0, :v0)"},
+		{"exists", Exists("A"), "attribute_exists(#n0)"},
+		{"not exists", NotExists("A"), "attribute_not_exists(#n0)"},
+		{"attribute type", AttributeType("A", "SS"), "attribute_type(#n0, :v0)"},
+		{"in", In("A", N(1), N(2), N(3)), "#n0 in (:v0, :v1, :v2)"},
+		{"in one", In("A", N(1)), "#n0 in (:v0)"},
+		{"size", Size("A").Gt(N(3)), "size(#n0) > :v0"},
+		{"path", Eq("A.B[1]", N(1)), "#n0.#n1[1] = :v0"},
+		{"built path", Exists(Path("A").Index(0).Field("A")), "attribute_exists(#n0[0].#n0)"},
+		{"string variable", Eq(name, N(1)).And(Size(name).Gt(N(1))), "#n0 = :v0 and size(#n0) > :v1"},
+		{
+			"size and field",
+			Size("A").Bt(N(1), N(2)).And(Eq("A", S("abc"))),
+			"size(#n0) between :v0 and :v1 and #n0 = :v2",
+		},
+		{
+			"and chain",
+			Eq("A", N(1)).And(Eq("B", N(2))).And(Eq("C", N(3))),
+			"#n0 = :v0 and #n1 = :v1 and #n2 = :v2",
+		},
+		{
+			"or then and",
+			Eq("A", N(1)).Or(Eq("B", N(2))).And(Not(Lt("C", N(3)))),
+			"(#n0 = :v0 or #n1 = :v1) and not #n2 < :v2",
+		},
+		{
+			"and then or",
+			Eq("A", N(1)).And(Eq("B", N(2))).Or(Eq("C", N(3))),
+			"(#n0 = :v0 and #n1 = :v1) or #n2 = :v2",
+		},
+		{
+			"nested",
+			Eq("A", N(1)).And(Eq("B", N(2)).Or(Eq("C", N(3)))),
+			"#n0 = :v0 and (#n1 = :v1 or #n2 = :v2)",
+		},
+		{
+			"group",
+			Group(Eq("A", N(1)).Or(Eq("B", N(2)))).And(Eq("C", N(3))),
+			"(#n0 = :v0 or #n1 = :v1) and #n2 = :v2",
+		},
+		{
+			"not group",
+			Not(Eq("A", N(1)).And(Eq("B", N(2)))),
+			"not (#n0 = :v0 and #n1 = :v1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.condition.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCondition_buildExpr(t *testing.T) {
+	b := newExprBuilder()
+	expr := Gte("Age", N(18)).
+		And(Lte("Age", N(65))).
+		And(Bt("Status", N(1), N(2))).
+		And(NotExists("Name")).
+		buildExpr(b)
+
+	if want := "#n0 >= :v0 and #n0 <= :v1 and #n1 between :v2 and :v3 and attribute_not_exists(#n2)"; *expr != want {
+		t.Errorf("buildExpr() = %v, want %v", *expr, want)
+	}
+
+	wantNames := map[string]string{"#n0": "Age", "#n1": "Status", "#n2": "Name"}
+	if !reflect.DeepEqual(b.attributeNames(), wantNames) {
+		t.Errorf("buildExpr() names = %v, want %v", b.attributeNames(), wantNames)
+	}
+
+	wantValues := map[string]interface{}{":v0": 18, ":v1": 65, ":v2": 1, ":v3": 2}
+	if !reflect.DeepEqual(b.values, wantValues) {
+		t.Errorf("buildExpr() values = %v, want %v", b.values, wantValues)
+	}
+}
+
+func TestCondition_And(t *testing.T) {
+	base := Eq("A", N(1))
+	first := base.And(Eq("B", N(2)))
+	second := base.And(Eq("C", N(3)))
+
+	b := newExprBuilder()
+	first.buildExpr(b)
+	second.buildExpr(b)
+
+	want := map[string]string{"#n0": "A", "#n1": "B", "#n2": "C"}
+	if !reflect.DeepEqual(b.attributeNames(), want) {
+		t.Errorf("And() names = %v, want %v", b.attributeNames(), want)
+	}
+}
+
+func TestCondition_splitKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		condition  Condition
+		wantKey    string
+		wantFilter string
+		wantErr    bool
+	}{
+		{"hash only", Eq("Id", N(1)), "#n0 = :v0", "", false},
+		{
+			"hash and range",
+			Gt("Age", N(1)).And(Eq("Id", N(2))),
+			"#n0 = :v0 and #n1 > :v1",
+			"",
+			false,
+		},
+		{
+			"filters",
+			Eq("Id", N(1)).
+				And(BeginsWith("Age", "1")).
+				And(Eq("Name", S("abc"))).
+				And(Lt("Age", N(3))).
+				And(Eq("Id", N(2)).Or(Eq("Name", S("def")))),
+			"#n0 = :v0 and begins_with(#n1, :v1)",
+			"#n0 = :v0 and #n1 < :v1 and (#n2 = :v2 or #n0 = :v3)",
+			false,
+		},
+		{"size is not a key", Size("Id").Eq(N(1)), "", "", true},
+		{"range only", Eq("Age", N(1)), "", "", true},
+		{"or", Eq("Id", N(1)).Or(Eq("Id", N(2))), "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, filter, err := tt.condition.splitKey("Id", "Age")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := key.String(); got != tt.wantKey {
+				t.Errorf("splitKey() key = %v, want %v", got, tt.wantKey)
+			}
+
+			if got := filter.String(); got != tt.wantFilter {
+				t.Errorf("splitKey() filter = %v, want %v", got, tt.wantFilter)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package dynagotest
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"sort"
+)
+
+const (
+	maxBatchGet   = 100
+	maxBatchWrite = 25
+)
+
+// BatchGetItem reads the items with the keys, leaving out those which
+// do not exist. It never has unprocessed keys.
+func (e *Emulator) BatchGetItem(
+	ctx context.Context,
+	input *dynamodb.BatchGetItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.BatchGetItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := validateBatchSize(len(input.RequestItems), countKeys(input.RequestItems), maxBatchGet); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	type tableGet struct {
+		table      *table
+		keys       []item
+		projection []path
+	}
+
+	var gets []tableGet
+	for _, name := range sortedNames(input.RequestItems) {
+		request := input.RequestItems[name]
+		t, err := e.table(&name)
+		if err != nil {
+			return nil, err
+		}
+
+		keys, err := t.uniqueKeys(request.Keys)
+		if err != nil {
+			return nil, err
+		}
+
+		exprCtx := newExprContext(request.ExpressionAttributeNames, nil)
+		projection, err := parseOptionalProjection(request.ProjectionExpression, exprCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := exprCtx.unused(); err != nil {
+			return nil, err
+		}
+
+		gets = append(gets, tableGet{t, keys, projection})
+	}
+
+	responses := make(map[string][]map[string]types.AttributeValue)
+	for _, get := range gets {
+		items := []map[string]types.AttributeValue{}
+		for _, key := range get.keys {
+			if stored := get.table.get(key); stored != nil {
+				items = append(items, copyItem(project(stored, get.projection)))
+			}
+		}
+
+		responses[*get.table.description.TableName] = items
+	}
+
+	return &dynamodb.BatchGetItemOutput{
+		Responses:       responses,
+		UnprocessedKeys: map[string]types.KeysAndAttributes{},
+	}, nil
+}
+
+// BatchWriteItem puts and deletes the items, validating all of the
+// requests before any item is written. It never has unprocessed items.
+func (e *Emulator) BatchWriteItem(
+	ctx context.Context,
+	input *dynamodb.BatchWriteItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.BatchWriteItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	requests := 0
+	for _, tableRequests := range input.RequestItems {
+		requests += len(tableRequests)
+	}
+
+	if err := validateBatchSize(len(input.RequestItems), requests, maxBatchWrite); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var writes []*write
+	for _, name := range sortedNames(input.RequestItems) {
+		t, err := e.table(&name)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make(map[string]bool)
+		for _, request := range input.RequestItems[name] {
+			var w *write
+			switch {
+			case request.PutRequest != nil && request.DeleteRequest == nil:
+				w, err = newPut(t, request.PutRequest.Item, nil, newExprContext(nil, nil))
+			case request.DeleteRequest != nil && request.PutRequest == nil:
+				w, err = newDelete(t, request.DeleteRequest.Key, nil, newExprContext(nil, nil))
+			default:
+				err = validationError("A WriteRequest must have exactly one of a PutRequest and a DeleteRequest")
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			id := t.keyID(w.key)
+			if keys[id] {
+				return nil, validationError("Provided list of item keys contains duplicates")
+			}
+
+			keys[id] = true
+
+			writes = append(writes, w)
+		}
+	}
+
+	for _, w := range writes {
+		if _, _, err := w.run(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{}}, nil
+}
+
+// uniqueKeys validates the keys, which must not have duplicates.
+func (t *table) uniqueKeys(keyValues []map[string]types.AttributeValue) ([]item, error) {
+	ids := make(map[string]bool)
+	keys := make([]item, len(keyValues))
+	for i, values := range keyValues {
+		key, err := t.key(values, true)
+		if err != nil {
+			return nil, err
+		}
+
+		id := t.keyID(key)
+		if ids[id] {
+			return nil, validationError("Provided list of item keys contains duplicates")
+		}
+
+		ids[id] = true
+		keys[i] = key
+	}
+
+	return keys, nil
+}
+
+func validateBatchSize(tables, requests, limit int) error {
+	if tables == 0 || requests == 0 {
+		return validationError("1 validation error detected: Value at 'requestItems' failed to satisfy " +
+			"constraint: Member must have length greater than or equal to 1")
+	}
+
+	if requests > limit {
+		return validationError("Too many items requested for the batch call; the maximum is %d", limit)
+	}
+
+	return nil
+}
+
+func countKeys(requests map[string]types.KeysAndAttributes) int {
+	keys := 0
+	for _, request := range requests {
+		keys += len(request.Keys)
+	}
+
+	return keys
+}
+
+func sortedNames[V any](requests map[string]V) []string {
+	names := make([]string, 0, len(requests))
+	for name := range requests {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,402 @@
+// Package dynagotest provides an in-process emulator of DynamoDb, so
+// that code built on dynago is tested with a plain go test, without a
+// running DynamoDB Local.
+//
+//  func TestMain(m *testing.M) {
+//    dynago.UpdateAPI(dynagotest.New())
+//    os.Exit(m.Run())
+//  }
+//
+// The Emulator implements the part of the DynamoDb API which dynago
+// uses: tables and their key schemas, key condition, filter, condition,
+// update and projection expressions, paginated queries and scans,
+// conditional writes, batches and transactions. It keeps the items in
+// memory and fails requests with the errors DynamoDb returns, but it
+// does not emulate secondary indexes, capacity or throttling.
+package dynagotest
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/eyebrow-fish/dynago"
+	"sort"
+	"sync"
+	"time"
+)
+
+// pageBytes is the most data a page of a Query or Scan reads.
+const pageBytes = 1 << 20
+
+// Emulator is an in-memory DynamoDb. It is safe for concurrent use and
+// its zero value is not usable; create it with New.
+type Emulator struct {
+	// PageSize limits how many items a page of a Query or Scan reads, on
+	// top of the one megabyte DynamoDb reads at most. Zero means no limit.
+	// It makes pagination easy to test without many items.
+	PageSize int
+
+	mu           sync.Mutex
+	tables       map[string]*table
+	transactions map[string]transaction
+}
+
+var _ dynago.API = (*Emulator)(nil)
+
+// New creates an Emulator without any tables.
+func New() *Emulator {
+	return &Emulator{tables: make(map[string]*table), transactions: make(map[string]transaction)}
+}
+
+// table holds the items of a table by partition, which are the items
+// with the same hash key sorted by their range key.
+type table struct {
+	description types.TableDescription
+	hashKey     string
+	rangeKey    string
+	keyTypes    map[string]string
+	partitions  map[string]*partition
+}
+
+type partition struct {
+	hash  string
+	items []item
+}
+
+func (e *Emulator) table(name *string) (*table, error) {
+	if name == nil || *name == "" {
+		return nil, validationError("1 validation error detected: Value null at 'tableName' failed to satisfy " +
+			"constraint: Member must not be null")
+	}
+
+	t, ok := e.tables[*name]
+	if !ok {
+		return nil, resourceNotFound()
+	}
+
+	return t, nil
+}
+
+// CreateTable creates an active table with the key schema. Only the key
+// attributes may be defined, as there are no secondary indexes.
+func (e *Emulator) CreateTable(
+	ctx context.Context,
+	input *dynamodb.CreateTableInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.CreateTableOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if input.TableName == nil || *input.TableName == "" {
+		return nil, validationError("TableName must be at least 3 characters long and at most 255 characters long")
+	}
+
+	if len(input.GlobalSecondaryIndexes) > 0 || len(input.LocalSecondaryIndexes) > 0 {
+		return nil, validationError("Secondary indexes are not supported by the emulator")
+	}
+
+	keyTypes := make(map[string]string)
+	for _, definition := range input.AttributeDefinitions {
+		if definition.AttributeName == nil {
+			return nil, validationError("One or more parameter values were invalid: " +
+				"Some AttributeDefinitions are missing an AttributeName")
+		}
+
+		switch definition.AttributeType {
+		case types.ScalarAttributeTypeS, types.ScalarAttributeTypeN, types.ScalarAttributeTypeB:
+		default:
+			return nil, validationError("Member must satisfy enum value set: [B, N, S]")
+		}
+
+		keyTypes[*definition.AttributeName] = string(definition.AttributeType)
+	}
+
+	t := &table{keyTypes: keyTypes, partitions: make(map[string]*partition)}
+	for i, element := range input.KeySchema {
+		if element.AttributeName == nil {
+			return nil, validationError("Invalid KeySchema: Some index key attribute have no definition")
+		}
+
+		switch {
+		case i == 0 && element.KeyType == types.KeyTypeHash:
+			t.hashKey = *element.AttributeName
+		case i == 1 && element.KeyType == types.KeyTypeRange:
+			t.rangeKey = *element.AttributeName
+		default:
+			return nil, validationError("Invalid KeySchema: The first KeySchemaElement is not a HASH key type " +
+				"or the second is not a RANGE key type")
+		}
+
+		if _, ok := keyTypes[*element.AttributeName]; !ok {
+			return nil, validationError("One or more parameter values were invalid: " +
+				"Some index key attributes are not defined in AttributeDefinitions")
+		}
+	}
+
+	if len(input.KeySchema) == 0 || len(input.KeySchema) > 2 || t.rangeKey == t.hashKey {
+		return nil, validationError("1 validation error detected: Value at 'keySchema' failed to satisfy " +
+			"constraint: Member must have length less than or equal to 2")
+	}
+
+	if len(keyTypes) != len(input.KeySchema) {
+		return nil, validationError("One or more parameter values were invalid: Number of attributes in " +
+			"KeySchema does not exactly match number of attributes defined in AttributeDefinitions")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.tables[*input.TableName]; ok {
+		return nil, resourceInUse(*input.TableName)
+	}
+
+	now := time.Now()
+	t.description = types.TableDescription{
+		TableName:            aws.String(*input.TableName),
+		TableArn:             aws.String("arn:aws:dynamodb:ddblocal:000000000000:table/" + *input.TableName),
+		TableStatus:          types.TableStatusActive,
+		CreationDateTime:     &now,
+		AttributeDefinitions: append([]types.AttributeDefinition(nil), input.AttributeDefinitions...),
+		KeySchema:            append([]types.KeySchemaElement(nil), input.KeySchema...),
+	}
+
+	if input.BillingMode != "" {
+		t.description.BillingModeSummary = &types.BillingModeSummary{BillingMode: input.BillingMode}
+	}
+
+	if input.ProvisionedThroughput != nil {
+		t.description.ProvisionedThroughput = &types.ProvisionedThroughputDescription{
+			ReadCapacityUnits:  input.ProvisionedThroughput.ReadCapacityUnits,
+			WriteCapacityUnits: input.ProvisionedThroughput.WriteCapacityUnits,
+		}
+	}
+
+	e.tables[*input.TableName] = t
+
+	return &dynamodb.CreateTableOutput{TableDescription: t.describe()}, nil
+}
+
+// DescribeTable describes the table, counting its items and their size.
+func (e *Emulator) DescribeTable(
+	ctx context.Context,
+	input *dynamodb.DescribeTableInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.DescribeTableOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, err := e.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.DescribeTableOutput{Table: t.describe()}, nil
+}
+
+// ListTables lists the names of the tables in alphabetical order, a
+// hundred at most per page.
+func (e *Emulator) ListTables(
+	ctx context.Context,
+	input *dynamodb.ListTablesInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.ListTablesOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	limit := 100
+	if input.Limit != nil {
+		if *input.Limit < 1 || *input.Limit > 100 {
+			return nil, validationError("1 validation error detected: Value '%d' at 'limit' failed to satisfy "+
+				"constraint: Member must have value between 1 and 100", *input.Limit)
+		}
+
+		limit = int(*input.Limit)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var names []string
+	for name := range e.tables {
+		if input.ExclusiveStartTableName == nil || name > *input.ExclusiveStartTableName {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	output := &dynamodb.ListTablesOutput{TableNames: names}
+	if len(names) > limit {
+		output.TableNames = names[:limit]
+		output.LastEvaluatedTableName = aws.String(names[limit-1])
+	}
+
+	return output, nil
+}
+
+func (t *table) describe() *types.TableDescription {
+	description := t.description
+	description.ItemCount = 0
+	description.TableSizeBytes = 0
+	for _, p := range t.partitions {
+		for _, stored := range p.items {
+			description.ItemCount++
+			description.TableSizeBytes += int64(itemSize(stored))
+		}
+	}
+
+	return &description
+}
+
+// key validates the key of an item, which must have the key attributes
+// with their defined types. When the values are only the key, they must
+// not have any other attributes.
+func (t *table) key(values item, onlyKey bool) (item, error) {
+	if values == nil {
+		return nil, validationError("The provided key element does not match the schema")
+	}
+
+	key := item{}
+	for _, name := range []string{t.hashKey, t.rangeKey} {
+		if name == "" {
+			continue
+		}
+
+		value, ok := values[name]
+		if !ok {
+			return nil, validationError("One or more parameter values were invalid: Missing the key %s in the item",
+				name)
+		}
+
+		if typeName(value) != t.keyTypes[name] {
+			return nil, validationError("One or more parameter values were invalid: Type mismatch for key %s "+
+				"expected: %s actual: %s", name, t.keyTypes[name], typeName(value))
+		}
+
+		if err := validateValue(value); err != nil {
+			return nil, err
+		}
+
+		if size(value) == 0 {
+			return nil, validationError("One or more parameter values are not valid. The AttributeValue for a "+
+				"key attribute cannot contain an empty string value. Key: %s", name)
+		}
+
+		key[name] = value
+	}
+
+	if onlyKey && len(values) != len(key) {
+		return nil, validationError("The provided key element does not match the schema")
+	}
+
+	return key, nil
+}
+
+// keyID identifies the key, so that equal keys have the same ID.
+func (t *table) keyID(key item) string {
+	id := keyString(key[t.hashKey])
+	if t.rangeKey != "" {
+		id += "\x00" + keyString(key[t.rangeKey])
+	}
+
+	return id
+}
+
+// find looks up the partition of the key and where in it the item with
+// the key is, or would be.
+func (t *table) find(key item) (*partition, int, bool) {
+	p := t.partitions[keyString(key[t.hashKey])]
+	if p == nil {
+		return nil, 0, false
+	}
+
+	if t.rangeKey == "" {
+		return p, 0, len(p.items) > 0
+	}
+
+	rangeValue := key[t.rangeKey]
+	i := sort.Search(len(p.items), func(i int) bool {
+		c, _ := compare(p.items[i][t.rangeKey], rangeValue)
+		return c >= 0
+	})
+
+	if i < len(p.items) {
+		c, _ := compare(p.items[i][t.rangeKey], rangeValue)
+		return p, i, c == 0
+	}
+
+	return p, i, false
+}
+
+// get returns the stored item with the key, or nil.
+func (t *table) get(key item) item {
+	p, i, found := t.find(key)
+	if !found {
+		return nil
+	}
+
+	return p.items[i]
+}
+
+// put stores a copy of the item, replacing any item with its key.
+func (t *table) put(values item) {
+	stored := copyItem(values)
+	p, i, found := t.find(values)
+	switch {
+	case found:
+		p.items[i] = stored
+	case p == nil:
+		hash := keyString(values[t.hashKey])
+		t.partitions[hash] = &partition{hash: hash, items: []item{stored}}
+	default:
+		p.items = append(p.items, nil)
+		copy(p.items[i+1:], p.items[i:])
+		p.items[i] = stored
+	}
+}
+
+// remove deletes the item with the key, along with its partition when
+// it was the last of it.
+func (t *table) remove(key item) {
+	p, i, found := t.find(key)
+	if !found {
+		return
+	}
+
+	p.items = append(p.items[:i], p.items[i+1:]...)
+	if len(p.items) == 0 {
+		delete(t.partitions, p.hash)
+	}
+}
+
+// sortedPartitions orders the partitions by their hash keys, which is
+// the order a Scan reads them in.
+func (t *table) sortedPartitions() []*partition {
+	partitions := make([]*partition, 0, len(t.partitions))
+	for _, p := range t.partitions {
+		partitions = append(partitions, p)
+	}
+
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].hash < partitions[j].hash })
+
+	return partitions
+}
+
+// after reports whether the key comes after the other key in the order
+// of a Scan.
+func (t *table) after(key, other item) bool {
+	hash, otherHash := keyString(key[t.hashKey]), keyString(other[t.hashKey])
+	if hash != otherHash || t.rangeKey == "" {
+		return hash > otherHash
+	}
+
+	c, _ := compare(key[t.rangeKey], other[t.rangeKey])
+	return c > 0
+}
@@ -0,0 +1,295 @@
+package dynagotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+// newTestEmulator creates an Emulator with the table Test, which has
+// the numeric hash key Id and the string range key Sort.
+func newTestEmulator(t *testing.T) *Emulator {
+	e := New()
+	_, err := e.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String("Test"),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("Id"), AttributeType: types.ScalarAttributeTypeN},
+			{AttributeName: aws.String("Sort"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("Id"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("Sort"), KeyType: types.KeyTypeRange},
+		},
+	})
+	assert.NoError(t, err)
+
+	return e
+}
+
+func put(t *testing.T, e *Emulator, values item) {
+	_, err := e.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String("Test"), Item: values})
+	assert.NoError(t, err)
+}
+
+func assertCode(t *testing.T, err error, code string) {
+	var apiErr smithy.APIError
+	if assert.True(t, errors.As(err, &apiErr), "%v", err) {
+		assert.Equal(t, code, apiErr.ErrorCode())
+	}
+}
+
+func TestEmulator_CreateTable(t *testing.T) {
+	e := newTestEmulator(t)
+
+	described, err := e.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("Test")})
+	assert.NoError(t, err)
+	assert.Equal(t, types.TableStatusActive, described.Table.TableStatus)
+
+	_, err = e.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:            aws.String("Test"),
+		AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String("Id"), AttributeType: "N"}},
+		KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("Id"), KeyType: types.KeyTypeHash}},
+	})
+	assertCode(t, err, "ResourceInUseException")
+
+	_, err = e.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String("Missing")})
+	assertCode(t, err, "ResourceNotFoundException")
+
+	listed, err := e.ListTables(ctx, &dynamodb.ListTablesInput{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Test"}, listed.TableNames)
+}
+
+func TestEmulator_PutItem(t *testing.T) {
+	e := newTestEmulator(t)
+	put(t, e, item{"Id": n("1"), "Sort": s("a"), "Value": n("1")})
+
+	_, err := e.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String("Test"),
+		Item:                item{"Id": n("1.0"), "Sort": s("a")},
+		ConditionExpression: aws.String("attribute_not_exists(Id)"),
+	})
+	assertCode(t, err, "ConditionalCheckFailedException")
+
+	_, err = e.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String("Test"), Item: item{"Id": n("1")}})
+	assertCode(t, err, "ValidationException")
+
+	got, err := e.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String("Test"),
+		Key:       item{"Id": n("1"), "Sort": s("a")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, item{"Id": n("1"), "Sort": s("a"), "Value": n("1")}, got.Item)
+}
+
+func TestEmulator_UpdateItem(t *testing.T) {
+	e := newTestEmulator(t)
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String("Test"),
+		Key:                       item{"Id": n("1"), "Sort": s("a")},
+		UpdateExpression:          aws.String("ADD #v :one"),
+		ExpressionAttributeNames:  map[string]string{"#v": "Value"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":one": n("1")},
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	}
+
+	for _, want := range []string{"1", "2"} {
+		updated, err := e.UpdateItem(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, item{"Value": n(want)}, updated.Attributes)
+	}
+
+	input.UpdateExpression = aws.String("SET Id = :one")
+	input.ExpressionAttributeNames = nil
+	_, err := e.UpdateItem(ctx, input)
+	assertCode(t, err, "ValidationException")
+}
+
+func TestEmulator_Query(t *testing.T) {
+	e := newTestEmulator(t)
+	for _, sort := range []string{"c", "a", "d", "b"} {
+		put(t, e, item{"Id": n("1"), "Sort": s(sort)})
+	}
+
+	put(t, e, item{"Id": n("2"), "Sort": s("a")})
+	e.PageSize = 2
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String("Test"),
+		KeyConditionExpression:    aws.String("Id = :id and Sort > :sort"),
+		FilterExpression:          aws.String("Sort <> :filtered"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":id": n("1"), ":sort": s("a"), ":filtered": s("c")},
+		ScanIndexForward:          aws.Bool(false),
+	}
+
+	var sorts []types.AttributeValue
+	var scanned int32
+	for pages := 0; pages == 0 || input.ExclusiveStartKey != nil; pages++ {
+		output, err := e.Query(ctx, input)
+		if !assert.NoError(t, err) || !assert.Less(t, pages, 3) {
+			return
+		}
+
+		for _, queried := range output.Items {
+			sorts = append(sorts, queried["Sort"])
+		}
+
+		scanned += output.ScannedCount
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	assert.Equal(t, []types.AttributeValue{s("d"), s("b")}, sorts)
+	assert.Equal(t, int32(3), scanned)
+}
+
+func TestEmulator_Query_invalidKeyCondition(t *testing.T) {
+	e := newTestEmulator(t)
+	tests := []string{
+		"Sort = :sort",
+		"Id > :id",
+		"Id = :id or Sort = :sort",
+		"Id = :id and Other = :sort",
+		"Id = :id and Sort = :sort and Sort = :sort",
+		"Id = :sort",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := e.Query(ctx, &dynamodb.QueryInput{
+				TableName:                 aws.String("Test"),
+				KeyConditionExpression:    aws.String(expr),
+				ExpressionAttributeValues: map[string]types.AttributeValue{":id": n("1"), ":sort": s("a")},
+			})
+			assertCode(t, err, "ValidationException")
+		})
+	}
+}
+
+func TestEmulator_Scan(t *testing.T) {
+	e := newTestEmulator(t)
+	for _, id := range []string{"3", "1", "2"} {
+		put(t, e, item{"Id": n(id), "Sort": s("a")})
+	}
+
+	output, err := e.Scan(ctx, &dynamodb.ScanInput{TableName: aws.String("Test"), Limit: aws.Int32(2)})
+	assert.NoError(t, err)
+	assert.Len(t, output.Items, 2)
+	assert.NotNil(t, output.LastEvaluatedKey)
+
+	output, err = e.Scan(ctx, &dynamodb.ScanInput{
+		TableName:         aws.String("Test"),
+		ExclusiveStartKey: output.LastEvaluatedKey,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, output.Items, 1)
+	assert.Nil(t, output.LastEvaluatedKey)
+}
+
+func TestEmulator_BatchWriteItem(t *testing.T) {
+	e := newTestEmulator(t)
+	request := types.WriteRequest{PutRequest: &types.PutRequest{Item: item{"Id": n("1"), "Sort": s("a")}}}
+
+	_, err := e.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{"Test": {request, request}},
+	})
+	assertCode(t, err, "ValidationException")
+
+	output, err := e.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{"Test": {request}},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, output.UnprocessedItems)
+
+	got, err := e.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{
+		RequestItems: map[string]types.KeysAndAttributes{
+			"Test": {Keys: []map[string]types.AttributeValue{
+				{"Id": n("1"), "Sort": s("a")},
+				{"Id": n("2"), "Sort": s("a")},
+			}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]types.AttributeValue{{"Id": n("1"), "Sort": s("a")}}, got.Responses["Test"])
+}
+
+func TestEmulator_TransactWriteItems(t *testing.T) {
+	e := newTestEmulator(t)
+	put(t, e, item{"Id": n("1"), "Sort": s("a")})
+
+	input := &dynamodb.TransactWriteItemsInput{TransactItems: []types.TransactWriteItem{
+		{Put: &types.Put{TableName: aws.String("Test"), Item: item{"Id": n("2"), "Sort": s("a")}}},
+		{ConditionCheck: &types.ConditionCheck{
+			TableName:                           aws.String("Test"),
+			Key:                                 item{"Id": n("1"), "Sort": s("a")},
+			ConditionExpression:                 aws.String("attribute_not_exists(Id)"),
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		}},
+	}}
+
+	_, err := e.TransactWriteItems(ctx, input)
+
+	var canceled *types.TransactionCanceledException
+	if assert.True(t, errors.As(err, &canceled)) {
+		assert.Equal(t, []types.CancellationReason{
+			{Code: aws.String("None")},
+			{
+				Code:    aws.String("ConditionalCheckFailed"),
+				Message: aws.String("The conditional request failed"),
+				Item:    item{"Id": n("1"), "Sort": s("a")},
+			},
+		}, canceled.CancellationReasons)
+	}
+
+	got, err := e.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String("Test"),
+		Key:       item{"Id": n("2"), "Sort": s("a")},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, got.Item)
+
+	input.TransactItems = append(input.TransactItems[:1], types.TransactWriteItem{
+		Delete: &types.Delete{TableName: aws.String("Test"), Key: item{"Id": n("2"), "Sort": s("a")}},
+	})
+	_, err = e.TransactWriteItems(ctx, input)
+	assertCode(t, err, "ValidationException")
+}
+
+func TestEmulator_TransactWriteItems_token(t *testing.T) {
+	e := newTestEmulator(t)
+	input := &dynamodb.TransactWriteItemsInput{
+		ClientRequestToken: aws.String("token"),
+		TransactItems: []types.TransactWriteItem{{Put: &types.Put{
+			TableName:           aws.String("Test"),
+			Item:                item{"Id": n("1"), "Sort": s("a")},
+			ConditionExpression: aws.String("attribute_not_exists(Id)"),
+		}}},
+	}
+
+	_, err := e.TransactWriteItems(ctx, input)
+	assert.NoError(t, err)
+
+	_, err = e.TransactWriteItems(ctx, input)
+	assert.NoError(t, err)
+
+	input.TransactItems[0].Put.Item = item{"Id": n("2"), "Sort": s("a")}
+	_, err = e.TransactWriteItems(ctx, input)
+	assertCode(t, err, "IdempotentParameterMismatchException")
+}
+
+func TestEmulator_canceled(t *testing.T) {
+	e := newTestEmulator(t)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err := e.Scan(canceled, &dynamodb.ScanInput{TableName: aws.String("Test")})
+	assert.ErrorIs(t, err, context.Canceled)
+}
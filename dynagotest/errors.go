@@ -0,0 +1,52 @@
+package dynagotest
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"strings"
+)
+
+// The errors are those which DynamoDb returns, so that code handling
+// them with errors.As behaves the same with the Emulator.
+
+func validationError(format string, args ...interface{}) error {
+	return &smithy.GenericAPIError{
+		Code:    "ValidationException",
+		Message: fmt.Sprintf(format, args...),
+		Fault:   smithy.FaultClient,
+	}
+}
+
+func resourceNotFound() error {
+	return &types.ResourceNotFoundException{Message: aws.String("Requested resource not found")}
+}
+
+func resourceInUse(name string) error {
+	return &types.ResourceInUseException{Message: aws.String("Table already exists: " + name)}
+}
+
+func conditionalCheckFailed() error {
+	return &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+}
+
+func idempotentParameterMismatch() error {
+	return &types.IdempotentParameterMismatchException{
+		Message: aws.String("Request token was used for a different transaction"),
+	}
+}
+
+// transactionCanceled lists the codes of the reasons in its message,
+// the way DynamoDb does.
+func transactionCanceled(reasons []types.CancellationReason) error {
+	codes := make([]string, len(reasons))
+	for i, reason := range reasons {
+		codes[i] = *reason.Code
+	}
+
+	message := "Transaction cancelled, please refer cancellation reasons for specific reasons [" +
+		strings.Join(codes, ", ") + "]"
+
+	return &types.TransactionCanceledException{Message: &message, CancellationReasons: reasons}
+}
@@ -0,0 +1,668 @@
+package dynagotest
+
+import (
+	"bytes"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// get finds the value at the path, which is nil when the item has no
+// such value.
+func (p path) get(doc item) types.AttributeValue {
+	var value types.AttributeValue = &types.AttributeValueMemberM{Value: doc}
+	for _, element := range p {
+		switch v := value.(type) {
+		case *types.AttributeValueMemberM:
+			if element.isIndex {
+				return nil
+			}
+
+			value = v.Value[element.name]
+		case *types.AttributeValueMemberL:
+			if !element.isIndex || element.index >= len(v.Value) {
+				return nil
+			}
+
+			value = v.Value[element.index]
+		default:
+			return nil
+		}
+
+		if value == nil {
+			return nil
+		}
+	}
+
+	return value
+}
+
+// set stores the value at the path, whose parent has to exist. Indexes
+// past the end of a list append to the list.
+func (p path) set(doc item, value types.AttributeValue) error {
+	last := p[len(p)-1]
+	switch v := p[:len(p)-1].get(doc).(type) {
+	case *types.AttributeValueMemberM:
+		if !last.isIndex {
+			v.Value[last.name] = value
+			return nil
+		}
+	case *types.AttributeValueMemberL:
+		if last.isIndex {
+			if last.index < len(v.Value) {
+				v.Value[last.index] = value
+			} else {
+				v.Value = append(v.Value, value)
+			}
+
+			return nil
+		}
+	}
+
+	return validationError("The document path provided in the update expression is invalid for update")
+}
+
+// remove removes the value at the path, if there is one. Removing an
+// element of a list shifts the elements after it.
+func (p path) remove(doc item) error {
+	last := p[len(p)-1]
+	switch v := p[:len(p)-1].get(doc).(type) {
+	case *types.AttributeValueMemberM:
+		if !last.isIndex {
+			delete(v.Value, last.name)
+			return nil
+		}
+	case *types.AttributeValueMemberL:
+		if last.isIndex {
+			if last.index < len(v.Value) {
+				v.Value = append(v.Value[:last.index], v.Value[last.index+1:]...)
+			}
+
+			return nil
+		}
+	case nil:
+		return nil
+	}
+
+	return validationError("The document path provided in the update expression is invalid for update")
+}
+
+func (o pathOperand) value(doc item) (types.AttributeValue, error) { return o.path.get(doc), nil }
+
+func (o valueOperand) value(item) (types.AttributeValue, error) { return o.attributeValue, nil }
+
+func (o sizeOperand) value(doc item) (types.AttributeValue, error) {
+	var n int
+	switch v := o.path.get(doc).(type) {
+	case *types.AttributeValueMemberS:
+		n = utf8.RuneCountInString(v.Value)
+	case *types.AttributeValueMemberB:
+		n = len(v.Value)
+	case *types.AttributeValueMemberSS:
+		n = len(v.Value)
+	case *types.AttributeValueMemberNS:
+		n = len(v.Value)
+	case *types.AttributeValueMemberBS:
+		n = len(v.Value)
+	case *types.AttributeValueMemberL:
+		n = len(v.Value)
+	case *types.AttributeValueMemberM:
+		n = len(v.Value)
+	default:
+		return nil, nil
+	}
+
+	return &types.AttributeValueMemberN{Value: strconv.Itoa(n)}, nil
+}
+
+func (o funcOperand) value(doc item) (types.AttributeValue, error) {
+	first, err := o.args[0].value(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.name == "if_not_exists" && first != nil {
+		return first, nil
+	}
+
+	second, err := operandValue(o.args[1], doc)
+	if err != nil || o.name == "if_not_exists" {
+		return second, err
+	}
+
+	if first == nil {
+		return nil, missingOperand()
+	}
+
+	left, leftOk := first.(*types.AttributeValueMemberL)
+	right, rightOk := second.(*types.AttributeValueMemberL)
+	if !leftOk || !rightOk {
+		return nil, validationError("Invalid UpdateExpression: Incorrect operand type for operator or function; "+
+			"operator or function: list_append, operand type: %s", typeName(first))
+	}
+
+	values := append(append([]types.AttributeValue{}, left.Value...), right.Value...)
+
+	return &types.AttributeValueMemberL{Value: values}, nil
+}
+
+func (o arithOperand) value(doc item) (types.AttributeValue, error) {
+	var numbers [2]*big.Rat
+	for i, side := range []operand{o.left, o.right} {
+		value, err := operandValue(side, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		n, ok := value.(*types.AttributeValueMemberN)
+		if !ok {
+			return nil, validationError("Invalid UpdateExpression: Incorrect operand type for operator or function; "+
+				"operator: %s, operand type: %s", o.op, typeName(value))
+		}
+
+		if numbers[i], err = parseNumber(n.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.op == "+" {
+		return &types.AttributeValueMemberN{Value: formatNumber(numbers[0].Add(numbers[0], numbers[1]))}, nil
+	}
+
+	return &types.AttributeValueMemberN{Value: formatNumber(numbers[0].Sub(numbers[0], numbers[1]))}, nil
+}
+
+// operandValue is the value of an operand of an update, which has to
+// exist.
+func operandValue(o operand, doc item) (types.AttributeValue, error) {
+	value, err := o.value(doc)
+	if err == nil && value == nil {
+		return nil, missingOperand()
+	}
+
+	return value, err
+}
+
+func missingOperand() error {
+	return validationError("The provided expression refers to an attribute that does not exist in the item")
+}
+
+func (c boolCondition) eval(doc item) (bool, error) {
+	left, err := c.left.eval(doc)
+	if err != nil || left != c.and {
+		return left, err
+	}
+
+	return c.right.eval(doc)
+}
+
+func (c notCondition) eval(doc item) (bool, error) {
+	inner, err := c.inner.eval(doc)
+
+	return !inner, err
+}
+
+func (c compareCondition) eval(doc item) (bool, error) {
+	left, right, err := operandValues(doc, c.left, c.right)
+	if err != nil {
+		return false, err
+	}
+
+	if left == nil || right == nil {
+		return c.op == "<>", nil
+	}
+
+	switch c.op {
+	case "=":
+		return equal(left, right), nil
+	case "<>":
+		return !equal(left, right), nil
+	}
+
+	order, ok := compare(left, right)
+	if !ok {
+		return false, nil
+	}
+
+	switch c.op {
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	default:
+		return order >= 0, nil
+	}
+}
+
+func (c betweenCondition) eval(doc item) (bool, error) {
+	subject, lower, err := operandValues(doc, c.subject, c.lower)
+	if err != nil {
+		return false, err
+	}
+
+	upper, err := c.upper.value(doc)
+	if err != nil || subject == nil || lower == nil || upper == nil {
+		return false, err
+	}
+
+	if order, ok := compare(lower, upper); ok && order > 0 {
+		return false, validationError("The BETWEEN operator requires upper bound "+
+			"to be greater than or equal to lower bound; lower operand: %v, upper operand: %v", lower, upper)
+	}
+
+	fromLower, okLower := compare(subject, lower)
+	toUpper, okUpper := compare(subject, upper)
+
+	return okLower && okUpper && fromLower >= 0 && toUpper <= 0, nil
+}
+
+func (c inCondition) eval(doc item) (bool, error) {
+	subject, err := c.subject.value(doc)
+	if err != nil || subject == nil {
+		return false, err
+	}
+
+	for _, o := range c.values {
+		value, err := o.value(doc)
+		if err != nil {
+			return false, err
+		}
+
+		if value != nil && equal(subject, value) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (c funcCondition) eval(doc item) (bool, error) {
+	subject, err := c.args[0].value(doc)
+	if err != nil {
+		return false, err
+	}
+
+	var arg types.AttributeValue
+	if len(c.args) > 1 {
+		if arg, err = c.args[1].value(doc); err != nil {
+			return false, err
+		}
+	}
+
+	switch c.name {
+	case "attribute_exists":
+		return subject != nil, nil
+	case "attribute_not_exists":
+		return subject == nil, nil
+	case "attribute_type":
+		name, ok := arg.(*types.AttributeValueMemberS)
+		if !ok || !isTypeName(name.Value) {
+			return false, validationError("Invalid ConditionExpression: Invalid attribute type name found; "+
+				"type: %v, valid types: { B,NULL,SS,BOOL,L,BS,N,NS,S,M }", arg)
+		}
+
+		return subject != nil && typeName(subject) == name.Value, nil
+	case "begins_with":
+		switch s := subject.(type) {
+		case *types.AttributeValueMemberS:
+			prefix, ok := arg.(*types.AttributeValueMemberS)
+			return ok && strings.HasPrefix(s.Value, prefix.Value), nil
+		case *types.AttributeValueMemberB:
+			prefix, ok := arg.(*types.AttributeValueMemberB)
+			return ok && bytes.HasPrefix(s.Value, prefix.Value), nil
+		default:
+			return false, nil
+		}
+	default:
+		return contains(subject, arg), nil
+	}
+}
+
+// contains is the contains function, which finds substrings in strings
+// and elements in sets and lists.
+func contains(subject, arg types.AttributeValue) bool {
+	if subject == nil || arg == nil {
+		return false
+	}
+
+	switch s := subject.(type) {
+	case *types.AttributeValueMemberS:
+		sub, ok := arg.(*types.AttributeValueMemberS)
+		return ok && strings.Contains(s.Value, sub.Value)
+	case *types.AttributeValueMemberB:
+		sub, ok := arg.(*types.AttributeValueMemberB)
+		return ok && bytes.Contains(s.Value, sub.Value)
+	case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+		elemType := typeName(subject)[:1]
+
+		return typeName(arg) == elemType && setMembers(subject)[setMember(arg)]
+	case *types.AttributeValueMemberL:
+		for _, elem := range s.Value {
+			if equal(elem, arg) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// setMember is the canonical member of a set for the scalar value.
+func setMember(value types.AttributeValue) string {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		if r, err := parseNumber(v.Value); err == nil {
+			return formatNumber(r)
+		}
+
+		return v.Value
+	case *types.AttributeValueMemberB:
+		return string(v.Value)
+	default:
+		return ""
+	}
+}
+
+func isTypeName(name string) bool {
+	switch name {
+	case "S", "N", "B", "BOOL", "NULL", "SS", "NS", "BS", "L", "M":
+		return true
+	default:
+		return false
+	}
+}
+
+func operandValues(doc item, left, right operand) (types.AttributeValue, types.AttributeValue, error) {
+	l, err := left.value(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := right.value(doc)
+
+	return l, r, err
+}
+
+// matches evaluates an optional condition, which no condition always
+// meets.
+func matches(c condition, doc item) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+
+	if doc == nil {
+		doc = item{}
+	}
+
+	return c.eval(doc)
+}
+
+// apply runs the update on a copy of the item, leaving the item itself
+// untouched. Like DynamoDb, every operand is evaluated against the item
+// as it was before the update.
+func (u *updateExpr) apply(old item) (item, error) {
+	if old == nil {
+		old = item{}
+	}
+
+	values := make([]types.AttributeValue, len(u.sets))
+	for i, action := range u.sets {
+		value, err := operandValue(action.operand, old)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = copyValue(value)
+	}
+
+	doc := copyItem(old)
+	for i, action := range u.sets {
+		if err := action.path.set(doc, values[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Removing the last elements of a list first keeps the indexes of
+	// the others the same as in the expression.
+	removes := append([]path(nil), u.removes...)
+	sort.SliceStable(removes, func(i, j int) bool { return lastIndex(removes[i]) > lastIndex(removes[j]) })
+	for _, removed := range removes {
+		if err := removed.remove(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, action := range u.adds {
+		added, err := add(action.path.get(doc), action.value)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := action.path.set(doc, added); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, action := range u.deletes {
+		remaining, err := deleteFromSet(action.path.get(doc), action.value)
+		if err != nil {
+			return nil, err
+		}
+
+		if remaining == nil {
+			err = action.path.remove(doc)
+		} else {
+			err = action.path.set(doc, remaining)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return doc, nil
+}
+
+func lastIndex(p path) int {
+	if last := p[len(p)-1]; last.isIndex {
+		return last.index
+	}
+
+	return -1
+}
+
+// add is the ADD action, which adds to numbers and sets.
+func add(existing, value types.AttributeValue) (types.AttributeValue, error) {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberN:
+		if existing == nil {
+			return copyValue(v), nil
+		}
+
+		n, ok := existing.(*types.AttributeValueMemberN)
+		if !ok {
+			break
+		}
+
+		a, err := parseNumber(n.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := parseNumber(v.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.AttributeValueMemberN{Value: formatNumber(a.Add(a, b))}, nil
+	case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+		if existing == nil {
+			return copyValue(v), nil
+		}
+
+		if typeName(existing) != typeName(value) {
+			break
+		}
+
+		return mergeSets(existing, value, true), nil
+	}
+
+	return nil, validationError("Invalid UpdateExpression: Incorrect operand type for operator or function; "+
+		"operator: ADD, operand type: %s", typeName(value))
+}
+
+// deleteFromSet is the DELETE action, which removes elements from a
+// set. The result is nil when no elements remain.
+func deleteFromSet(existing, value types.AttributeValue) (types.AttributeValue, error) {
+	switch value.(type) {
+	case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+	default:
+		return nil, validationError("Invalid UpdateExpression: Incorrect operand type for operator or function; "+
+			"operator: DELETE, operand type: %s", typeName(value))
+	}
+
+	if existing == nil {
+		return nil, nil
+	}
+
+	if typeName(existing) != typeName(value) {
+		return nil, validationError("An operand in the update expression has an incorrect data type")
+	}
+
+	return mergeSets(existing, value, false), nil
+}
+
+// mergeSets adds the members of other to the set, or removes them from
+// it, keeping the order of the members. It returns nil for empty sets.
+func mergeSets(set, other types.AttributeValue, union bool) types.AttributeValue {
+	candidates := members(set)
+	if union {
+		candidates = append(candidates, members(other)...)
+	}
+
+	removed := setMembers(other)
+	seen := make(map[string]bool)
+	var kept []string
+	for _, m := range candidates {
+		if seen[m.canonical] || !union && removed[m.canonical] {
+			continue
+		}
+
+		seen[m.canonical] = true
+		kept = append(kept, m.raw)
+	}
+
+	if len(kept) == 0 {
+		return nil
+	}
+
+	switch set.(type) {
+	case *types.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: kept}
+	case *types.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: kept}
+	default:
+		binaries := make([][]byte, len(kept))
+		for i, member := range kept {
+			binaries[i] = []byte(member)
+		}
+
+		return &types.AttributeValueMemberBS{Value: binaries}
+	}
+}
+
+// project keeps only the values at the paths, where the projected
+// elements of a list keep their order but not their indexes.
+func project(doc item, paths []path) item {
+	if doc == nil || paths == nil {
+		return doc
+	}
+
+	root := &projection{}
+	for _, projected := range paths {
+		value := projected.get(doc)
+		if value == nil {
+			continue
+		}
+
+		node := root
+		for _, element := range projected {
+			node = node.child(element)
+		}
+
+		node.value = value
+	}
+
+	projected := item{}
+	for name, child := range root.fields {
+		projected[name] = child.build()
+	}
+
+	return projected
+}
+
+// projection is a node of the tree of projected paths.
+type projection struct {
+	value    types.AttributeValue
+	fields   map[string]*projection
+	elements map[int]*projection
+}
+
+func (p *projection) child(element pathElement) *projection {
+	if element.isIndex {
+		if p.elements == nil {
+			p.elements = make(map[int]*projection)
+		}
+
+		if p.elements[element.index] == nil {
+			p.elements[element.index] = &projection{}
+		}
+
+		return p.elements[element.index]
+	}
+
+	if p.fields == nil {
+		p.fields = make(map[string]*projection)
+	}
+
+	if p.fields[element.name] == nil {
+		p.fields[element.name] = &projection{}
+	}
+
+	return p.fields[element.name]
+}
+
+func (p *projection) build() types.AttributeValue {
+	switch {
+	case p.value != nil:
+		return copyValue(p.value)
+	case p.elements != nil:
+		var indexes []int
+		for index := range p.elements {
+			indexes = append(indexes, index)
+		}
+
+		sort.Ints(indexes)
+
+		values := make([]types.AttributeValue, len(indexes))
+		for i, index := range indexes {
+			values[i] = p.elements[index].build()
+		}
+
+		return &types.AttributeValueMemberL{Value: values}
+	default:
+		fields := make(item, len(p.fields))
+		for name, child := range p.fields {
+			fields[name] = child.build()
+		}
+
+		return &types.AttributeValueMemberM{Value: fields}
+	}
+}
@@ -0,0 +1,760 @@
+package dynagotest
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// exprContext holds the ExpressionAttributeNames and -Values of a
+// request, which all of its expressions share. DynamoDb rejects
+// requests with placeholders which no expression uses.
+type exprContext struct {
+	names      map[string]string
+	values     map[string]types.AttributeValue
+	usedNames  map[string]bool
+	usedValues map[string]bool
+}
+
+func newExprContext(names map[string]string, values map[string]types.AttributeValue) *exprContext {
+	return &exprContext{names: names, values: values, usedNames: map[string]bool{}, usedValues: map[string]bool{}}
+}
+
+func (c *exprContext) name(placeholder string) (string, error) {
+	name, ok := c.names[placeholder]
+	if !ok {
+		return "", validationError("An expression attribute name used in the document path is not defined; "+
+			"attribute name: %s", placeholder)
+	}
+
+	c.usedNames[placeholder] = true
+
+	return name, nil
+}
+
+func (c *exprContext) value(placeholder string) (types.AttributeValue, error) {
+	value, ok := c.values[placeholder]
+	if !ok {
+		return nil, validationError("An expression attribute value used in expression is not defined; "+
+			"attribute value: %s", placeholder)
+	}
+
+	if err := validateValue(value); err != nil {
+		return nil, err
+	}
+
+	c.usedValues[placeholder] = true
+
+	return value, nil
+}
+
+// unused fails when some of the placeholders were not used by any of
+// the expressions.
+func (c *exprContext) unused() error {
+	var names, values []string
+	for placeholder := range c.names {
+		if !c.usedNames[placeholder] {
+			names = append(names, placeholder)
+		}
+	}
+
+	for placeholder := range c.values {
+		if !c.usedValues[placeholder] {
+			values = append(values, placeholder)
+		}
+	}
+
+	sort.Strings(names)
+	sort.Strings(values)
+
+	switch {
+	case len(names) > 0:
+		return validationError("Value provided in ExpressionAttributeNames unused in expressions: keys: {%s}",
+			strings.Join(names, ", "))
+	case len(values) > 0:
+		return validationError("Value provided in ExpressionAttributeValues unused in expressions: keys: {%s}",
+			strings.Join(values, ", "))
+	default:
+		return nil
+	}
+}
+
+// path is a document path, such as Address.Lines[0], with its names
+// resolved.
+type path []pathElement
+
+type pathElement struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+func (p path) String() string {
+	var s strings.Builder
+	for i, element := range p {
+		switch {
+		case element.isIndex:
+			s.WriteString("[" + strconv.Itoa(element.index) + "]")
+		case i > 0:
+			s.WriteString("." + element.name)
+		default:
+			s.WriteString(element.name)
+		}
+	}
+
+	return s.String()
+}
+
+// overlaps reports whether either path is the other or inside of it.
+func (p path) overlaps(other path) bool {
+	n := len(p)
+	if len(other) < n {
+		n = len(other)
+	}
+
+	for i := 0; i < n; i++ {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// operand is a value in an expression. Its value is nil when it refers
+// to an attribute which the item does not have.
+type operand interface {
+	value(doc item) (types.AttributeValue, error)
+}
+
+type (
+	pathOperand  struct{ path path }
+	valueOperand struct{ attributeValue types.AttributeValue }
+	sizeOperand  struct{ path path }
+	funcOperand  struct {
+		name string
+		args []operand
+	}
+	arithOperand struct {
+		op          string
+		left, right operand
+	}
+)
+
+// condition is a condition, filter or key condition expression.
+type condition interface {
+	eval(doc item) (bool, error)
+}
+
+type (
+	boolCondition struct {
+		and         bool
+		left, right condition
+	}
+	notCondition     struct{ inner condition }
+	compareCondition struct {
+		op          string
+		left, right operand
+	}
+	betweenCondition struct{ subject, lower, upper operand }
+	inCondition      struct {
+		subject operand
+		values  []operand
+	}
+	funcCondition struct {
+		name string
+		args []operand
+	}
+)
+
+// updateExpr is an update expression, with its actions by section.
+type updateExpr struct {
+	sets    []setAction
+	removes []path
+	adds    []valueAction
+	deletes []valueAction
+}
+
+type setAction struct {
+	path    path
+	operand operand
+}
+
+type valueAction struct {
+	path  path
+	value types.AttributeValue
+}
+
+// paths are the paths of all of the actions.
+func (u *updateExpr) paths() []path {
+	var paths []path
+	for _, action := range u.sets {
+		paths = append(paths, action.path)
+	}
+
+	paths = append(paths, u.removes...)
+	for _, actions := range [][]valueAction{u.adds, u.deletes} {
+		for _, action := range actions {
+			paths = append(paths, action.path)
+		}
+	}
+
+	return paths
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenName
+	tokenValue
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(kind, expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || c == ':' || isIdentStart(c):
+			start := i
+			i++
+			for i < len(expr) && isIdentPart(expr[i]) {
+				i++
+			}
+
+			tokenType := tokenIdent
+			switch c {
+			case '#':
+				tokenType = tokenName
+			case ':':
+				tokenType = tokenValue
+			}
+
+			if i-start == 1 && tokenType != tokenIdent {
+				return nil, validationError("Invalid %s: Syntax error; token: %q", kind, string(c))
+			}
+
+			tokens = append(tokens, token{tokenType, expr[start:i]})
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+				i++
+			}
+
+			tokens = append(tokens, token{tokenNumber, expr[start:i]})
+		case strings.HasPrefix(expr[i:], "<>") || strings.HasPrefix(expr[i:], "<=") || strings.HasPrefix(expr[i:], ">="):
+			tokens = append(tokens, token{tokenPunct, expr[i : i+2]})
+			i += 2
+		case strings.IndexByte("()[],.=<>+-", c) >= 0:
+			tokens = append(tokens, token{tokenPunct, string(c)})
+			i++
+		default:
+			return nil, validationError("Invalid %s: Invalid character encountered; character: %q", kind, string(c))
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+func isIdentStart(c byte) bool { return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+
+func isIdentPart(c byte) bool { return isIdentStart(c) || c >= '0' && c <= '9' }
+
+type parser struct {
+	kind   string
+	tokens []token
+	pos    int
+	ctx    *exprContext
+}
+
+func newParser(kind, expr string, ctx *exprContext) (*parser, error) {
+	tokens, err := tokenize(kind, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parser{kind: kind, tokens: tokens, ctx: ctx}, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+// accept consumes the punctuation or keyword when it is next.
+func (p *parser) accept(text string) bool {
+	t := p.peek()
+	if t.kind == tokenPunct && t.text == text || t.kind == tokenIdent && strings.EqualFold(t.text, text) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.syntaxError()
+	}
+
+	return nil
+}
+
+// isCall reports whether the next tokens are a function call.
+func (p *parser) isCall() bool {
+	next := p.tokens[p.pos+1:]
+	return p.peek().kind == tokenIdent && len(next) > 0 && next[0].kind == tokenPunct && next[0].text == "("
+}
+
+func (p *parser) syntaxError() error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return validationError("Invalid %s: Syntax error; token: \"<EOF>\"", p.kind)
+	}
+
+	return validationError("Invalid %s: Syntax error; token: %q", p.kind, t.text)
+}
+
+func (p *parser) end() error {
+	if p.peek().kind != tokenEOF {
+		return p.syntaxError()
+	}
+
+	return nil
+}
+
+func (p *parser) path() (path, error) {
+	var parsed path
+	for {
+		switch t := p.peek(); t.kind {
+		case tokenIdent:
+			if isReserved(t.text) {
+				return nil, validationError("Invalid %s: Attribute name is a reserved keyword; reserved keyword: %s",
+					p.kind, t.text)
+			}
+
+			parsed = append(parsed, pathElement{name: t.text})
+		case tokenName:
+			name, err := p.ctx.name(t.text)
+			if err != nil {
+				return nil, err
+			}
+
+			parsed = append(parsed, pathElement{name: name})
+		default:
+			return nil, p.syntaxError()
+		}
+
+		p.next()
+
+		for p.accept("[") {
+			index, err := strconv.Atoi(p.peek().text)
+			if p.peek().kind != tokenNumber || err != nil {
+				return nil, p.syntaxError()
+			}
+
+			p.next()
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+
+			parsed = append(parsed, pathElement{index: index, isIndex: true})
+		}
+
+		if !p.accept(".") {
+			return parsed, nil
+		}
+	}
+}
+
+// parseCondition parses a condition, filter or key condition expression.
+func parseCondition(kind, expr string, ctx *exprContext) (condition, error) {
+	p, err := newParser(kind, expr, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, p.end()
+}
+
+func (p *parser) or() (condition, error) {
+	left, err := p.and()
+	for err == nil && p.accept("OR") {
+		var right condition
+		if right, err = p.and(); err == nil {
+			left = boolCondition{false, left, right}
+		}
+	}
+
+	return left, err
+}
+
+func (p *parser) and() (condition, error) {
+	left, err := p.not()
+	for err == nil && p.accept("AND") {
+		var right condition
+		if right, err = p.not(); err == nil {
+			left = boolCondition{true, left, right}
+		}
+	}
+
+	return left, err
+}
+
+func (p *parser) not() (condition, error) {
+	if p.accept("NOT") {
+		inner, err := p.not()
+		return notCondition{inner}, err
+	}
+
+	if p.accept("(") {
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		return inner, p.expect(")")
+	}
+
+	if p.isCall() && !strings.EqualFold(p.peek().text, "size") {
+		return p.function()
+	}
+
+	return p.comparison()
+}
+
+// function parses the functions which are conditions themselves.
+func (p *parser) function() (condition, error) {
+	name := p.next().text
+	p.next()
+
+	var arity int
+	switch name {
+	case "attribute_exists", "attribute_not_exists":
+		arity = 1
+	case "attribute_type", "begins_with", "contains":
+		arity = 2
+	default:
+		return nil, validationError("Invalid %s: Invalid function name; function: %s", p.kind, name)
+	}
+
+	subject, err := p.path()
+	if err != nil {
+		return nil, err
+	}
+
+	args := []operand{pathOperand{subject}}
+	for len(args) < arity {
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+
+		arg, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+
+	return funcCondition{name, args}, p.expect(")")
+}
+
+func (p *parser) comparison() (condition, error) {
+	subject, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := p.peek(); {
+	case t.kind == tokenPunct && isComparator(t.text):
+		p.next()
+		right, err := p.operand()
+
+		return compareCondition{t.text, subject, right}, err
+	case p.accept("BETWEEN"):
+		lower, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+
+		upper, err := p.operand()
+
+		return betweenCondition{subject, lower, upper}, err
+	case p.accept("IN"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+
+		var values []operand
+		for {
+			value, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+			if !p.accept(",") {
+				return inCondition{subject, values}, p.expect(")")
+			}
+		}
+	default:
+		return nil, p.syntaxError()
+	}
+}
+
+func isComparator(text string) bool {
+	switch text {
+	case "=", "<>", "<", "<=", ">", ">=":
+		return true
+	default:
+		return false
+	}
+}
+
+// operand parses the operands of conditions, which are paths, values
+// and the size function.
+func (p *parser) operand() (operand, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenValue:
+		p.next()
+		value, err := p.ctx.value(t.text)
+
+		return valueOperand{value}, err
+	case p.isCall():
+		if t.text != "size" {
+			return nil, validationError("Invalid %s: The function is not allowed to be used this way "+
+				"in an expression; function: %s", p.kind, t.text)
+		}
+
+		p.pos += 2
+		subject, err := p.path()
+		if err != nil {
+			return nil, err
+		}
+
+		return sizeOperand{subject}, p.expect(")")
+	default:
+		subject, err := p.path()
+
+		return pathOperand{subject}, err
+	}
+}
+
+// parseUpdate parses an update expression.
+func parseUpdate(expr string, ctx *exprContext) (*updateExpr, error) {
+	p, err := newParser("UpdateExpression", expr, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &updateExpr{}
+	seen := make(map[string]bool)
+	for p.peek().kind != tokenEOF {
+		if p.peek().kind != tokenIdent {
+			return nil, p.syntaxError()
+		}
+
+		section := strings.ToUpper(p.next().text)
+		if seen[section] {
+			return nil, validationError("Invalid UpdateExpression: The %q section can only be used once "+
+				"in an update expression", section)
+		}
+
+		seen[section] = true
+
+		for {
+			switch section {
+			case "SET":
+				action, err := p.setAction()
+				if err != nil {
+					return nil, err
+				}
+
+				u.sets = append(u.sets, action)
+			case "REMOVE":
+				removed, err := p.path()
+				if err != nil {
+					return nil, err
+				}
+
+				u.removes = append(u.removes, removed)
+			case "ADD", "DELETE":
+				action, err := p.valueAction()
+				if err != nil {
+					return nil, err
+				}
+
+				if section == "ADD" {
+					u.adds = append(u.adds, action)
+				} else {
+					u.deletes = append(u.deletes, action)
+				}
+			default:
+				p.pos--
+				return nil, p.syntaxError()
+			}
+
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	paths := u.paths()
+	if len(paths) == 0 {
+		return nil, p.syntaxError()
+	}
+
+	for i := range paths {
+		for _, other := range paths[i+1:] {
+			if paths[i].overlaps(other) {
+				return nil, validationError("Invalid UpdateExpression: Two document paths overlap with each other; "+
+					"must remove or rewrite one of these paths; path one: [%s], path two: [%s]", paths[i], other)
+			}
+		}
+	}
+
+	return u, nil
+}
+
+func (p *parser) setAction() (setAction, error) {
+	target, err := p.path()
+	if err != nil {
+		return setAction{}, err
+	}
+
+	if err := p.expect("="); err != nil {
+		return setAction{}, err
+	}
+
+	value, err := p.setOperand()
+
+	return setAction{target, value}, err
+}
+
+func (p *parser) setOperand() (operand, error) {
+	left, err := p.setTerm()
+	for err == nil && (p.peek().text == "+" || p.peek().text == "-") && p.peek().kind == tokenPunct {
+		op := p.next().text
+
+		var right operand
+		if right, err = p.setTerm(); err == nil {
+			left = arithOperand{op, left, right}
+		}
+	}
+
+	return left, err
+}
+
+func (p *parser) setTerm() (operand, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenValue:
+		p.next()
+		value, err := p.ctx.value(t.text)
+
+		return valueOperand{value}, err
+	case p.isCall():
+		if t.text != "if_not_exists" && t.text != "list_append" {
+			return nil, validationError("Invalid UpdateExpression: Invalid function name; function: %s", t.text)
+		}
+
+		p.pos += 2
+
+		var first operand
+		if t.text == "if_not_exists" {
+			checked, err := p.path()
+			if err != nil {
+				return nil, err
+			}
+
+			first = pathOperand{checked}
+		} else {
+			var err error
+			if first, err = p.setTerm(); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+
+		second, err := p.setTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		return funcOperand{t.text, []operand{first, second}}, p.expect(")")
+	default:
+		operandPath, err := p.path()
+
+		return pathOperand{operandPath}, err
+	}
+}
+
+func (p *parser) valueAction() (valueAction, error) {
+	target, err := p.path()
+	if err != nil {
+		return valueAction{}, err
+	}
+
+	t := p.peek()
+	if t.kind != tokenValue {
+		return valueAction{}, p.syntaxError()
+	}
+
+	p.next()
+	value, err := p.ctx.value(t.text)
+
+	return valueAction{target, value}, err
+}
+
+// parseProjection parses a projection expression.
+func parseProjection(expr string, ctx *exprContext) ([]path, error) {
+	p, err := newParser("ProjectionExpression", expr, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []path
+	for {
+		projected, err := p.path()
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, projected)
+		if !p.accept(",") {
+			return paths, p.end()
+		}
+	}
+}
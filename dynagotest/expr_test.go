@@ -0,0 +1,191 @@
+package dynagotest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func n(value string) types.AttributeValue { return &types.AttributeValueMemberN{Value: value} }
+func s(value string) types.AttributeValue { return &types.AttributeValueMemberS{Value: value} }
+
+func Test_parseCondition(t *testing.T) {
+	doc := item{
+		"Id":    n("1"),
+		"Title": s("abc"),
+		"Tags":  &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+		"Elems": &types.AttributeValueMemberL{Value: []types.AttributeValue{n("1"), s("x")}},
+		"Doc":   &types.AttributeValueMemberM{Value: item{"A": n("2.50")}},
+	}
+
+	values := map[string]types.AttributeValue{":1": n("1"), ":2": n("2.5"), ":abc": s("ab"), ":t": s("SS")}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"Id = :1", true},
+		{"Id <> :1", false},
+		{"Id < :2 AND Title >= :abc", true},
+		{"Id > :1 or not Title = :abc", true},
+		{"(Id > :1 or Title = :abc) and Id = :1", false},
+		{"Doc.A = :2", true},
+		{"Elems[0] = :1", true},
+		{"Id between :1 and :2", true},
+		{"Id in (:2, :1)", true},
+		{"begins_with(Title, :abc)", true},
+		{"contains(Tags, :abc)", false},
+		{"attribute_exists(Doc.A)", true},
+		{"attribute_not_exists(Absent)", true},
+		{"attribute_type(Tags, :t)", true},
+		{"size(Elems) = :2 or size(Title) > :2", true},
+		{"Absent <> :1", true},
+		{"Absent = Absent", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := parseCondition("ConditionExpression", tt.expr, newExprContext(nil, values))
+			assert.NoError(t, err)
+
+			got, err := matches(c, doc)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseCondition_invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"Id =",
+		"Id = :missing",
+		"#missing = :1",
+		"Id = :1 and",
+		"unknown(Id)",
+		"Id == :1",
+		"Id[x] = :1",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := parseCondition("ConditionExpression", expr, newExprContext(nil, map[string]types.AttributeValue{
+				":1": n("1"),
+			}))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_exprContext_unused(t *testing.T) {
+	ctx := newExprContext(map[string]string{"#a": "A", "#b": "B"}, map[string]types.AttributeValue{":1": n("1")})
+	_, err := parseCondition("ConditionExpression", "#a = :1", ctx)
+	assert.NoError(t, err)
+	assert.EqualError(t, ctx.unused(), "api error ValidationException: "+
+		"Value provided in ExpressionAttributeNames unused in expressions: keys: {#b}")
+}
+
+func Test_parseUpdate(t *testing.T) {
+	old := item{
+		"Id":     n("1"),
+		"Visits": n("2"),
+		"Elems":  &types.AttributeValueMemberL{Value: []types.AttributeValue{n("1"), n("2"), n("3")}},
+		"Tags":   &types.AttributeValueMemberSS{Value: []string{"a", "b"}},
+		"Gone":   s("x"),
+		"Extra":  &types.AttributeValueMemberL{Value: []types.AttributeValue{n("1"), n("2")}},
+	}
+
+	values := map[string]types.AttributeValue{
+		":1":    n("1"),
+		":list": &types.AttributeValueMemberL{Value: []types.AttributeValue{n("4")}},
+		":tags": &types.AttributeValueMemberSS{Value: []string{"a", "c"}},
+	}
+
+	expr := "SET Visits = Visits + :1, Elems = list_append(Elems, :list), Fresh = if_not_exists(Fresh, :1) " +
+		"REMOVE Gone, Extra[0] ADD Tags :tags"
+	u, err := parseUpdate(expr, newExprContext(nil, values))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	updated, err := u.apply(old)
+	assert.NoError(t, err)
+
+	assert.Equal(t, item{
+		"Id":     n("1"),
+		"Visits": n("3"),
+		"Elems":  &types.AttributeValueMemberL{Value: []types.AttributeValue{n("1"), n("2"), n("3"), n("4")}},
+		"Tags":   &types.AttributeValueMemberSS{Value: []string{"a", "b", "c"}},
+		"Fresh":  n("1"),
+		"Extra":  &types.AttributeValueMemberL{Value: []types.AttributeValue{n("2")}},
+	}, updated)
+	assert.Equal(t, s("x"), old["Gone"])
+}
+
+func Test_parseUpdate_invalid(t *testing.T) {
+	tests := []string{
+		"SET A = :1 SET B = :1",
+		"SET A = :1, A = :1",
+		"SET A = :1 REMOVE A.B",
+		"REMOVE",
+		"ADD A",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := parseUpdate(expr, newExprContext(nil, map[string]types.AttributeValue{":1": n("1")}))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_project(t *testing.T) {
+	doc := item{
+		"Id":    n("1"),
+		"Elems": &types.AttributeValueMemberL{Value: []types.AttributeValue{n("1"), n("2"), n("3")}},
+		"Doc":   &types.AttributeValueMemberM{Value: item{"A": n("1"), "B": n("2")}},
+	}
+
+	paths, err := parseProjection("Id, Elems[2], Elems[0], Doc.B, Absent", newExprContext(nil, nil))
+	assert.NoError(t, err)
+
+	assert.Equal(t, item{
+		"Id":    n("1"),
+		"Elems": &types.AttributeValueMemberL{Value: []types.AttributeValue{n("1"), n("3")}},
+		"Doc":   &types.AttributeValueMemberM{Value: item{"B": n("2")}},
+	}, project(doc, paths))
+}
+
+func Test_reservedWords(t *testing.T) {
+	values := map[string]types.AttributeValue{":1": n("1")}
+	tests := []struct {
+		kind  string
+		parse func(expr string, ctx *exprContext) error
+		expr  string
+	}{
+		{"ConditionExpression", func(expr string, ctx *exprContext) error {
+			_, err := parseCondition("ConditionExpression", expr, ctx)
+			return err
+		}, "Id = :1 and Name = :1"},
+		{"UpdateExpression", func(expr string, ctx *exprContext) error {
+			_, err := parseUpdate(expr, ctx)
+			return err
+		}, "SET Id = :1, Name = :1"},
+		{"ProjectionExpression", func(expr string, ctx *exprContext) error {
+			_, err := parseProjection(expr, ctx)
+			return err
+		}, "Id, Name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			err := tt.parse(tt.expr, newExprContext(nil, values))
+			assert.EqualError(t, err, "api error ValidationException: Invalid "+tt.kind+
+				": Attribute name is a reserved keyword; reserved keyword: Name")
+
+			placeholders := strings.ReplaceAll(tt.expr, "Name", "#name")
+			assert.NoError(t, tt.parse(placeholders, newExprContext(map[string]string{"#name": "Name"}, values)))
+		})
+	}
+}
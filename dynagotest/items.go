@@ -0,0 +1,392 @@
+package dynagotest
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxItemSize is the size of the largest item DynamoDb stores.
+const maxItemSize = 400 * 1024
+
+// write is a validated put, update, delete or condition check of an
+// item. It is prepared before it is committed, so that a transaction
+// checks all of its writes before it changes any item.
+type write struct {
+	table     *table
+	key       item
+	condition condition
+	// change computes the item after the write from the stored item, or
+	// nil when the write deletes it. Condition checks do not have it.
+	change func(old item) (item, error)
+	// updated are the paths an update expression changes.
+	updated []path
+}
+
+func newPut(t *table, values item, conditionExpr *string, ctx *exprContext) (*write, error) {
+	key, err := t.key(values, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateItem(values); err != nil {
+		return nil, err
+	}
+
+	if itemSize(values) > maxItemSize {
+		return nil, validationError("Item size has exceeded the maximum allowed size")
+	}
+
+	w := &write{table: t, key: key, change: func(item) (item, error) { return values, nil }}
+
+	return w, w.parseCondition(conditionExpr, ctx)
+}
+
+func newDelete(t *table, keyValues item, conditionExpr *string, ctx *exprContext) (*write, error) {
+	key, err := t.key(keyValues, true)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &write{table: t, key: key, change: func(item) (item, error) { return nil, nil }}
+
+	return w, w.parseCondition(conditionExpr, ctx)
+}
+
+// newUpdate prepares an update of the item with the key, which creates
+// the item when there is none.
+func newUpdate(t *table, keyValues item, updateExpression, conditionExpr *string, ctx *exprContext) (*write, error) {
+	key, err := t.key(keyValues, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var update *updateExpr
+	if updateExpression != nil {
+		if update, err = parseUpdate(*updateExpression, ctx); err != nil {
+			return nil, err
+		}
+
+		for _, updated := range update.paths() {
+			if _, ok := key[updated[0].name]; ok {
+				return nil, validationError("One or more parameter values were invalid: Cannot update attribute "+
+					"%s. This attribute is part of the key", updated[0].name)
+			}
+		}
+	}
+
+	w := &write{table: t, key: key}
+	w.change = func(old item) (item, error) {
+		if old == nil {
+			old = key
+		}
+
+		if update == nil {
+			return copyItem(old), nil
+		}
+
+		updated, err := update.apply(old)
+		if err != nil {
+			return nil, err
+		}
+
+		if itemSize(updated) > maxItemSize {
+			return nil, validationError("Item size to update has exceeded the maximum allowed size")
+		}
+
+		return updated, nil
+	}
+
+	if update != nil {
+		w.updated = update.paths()
+	}
+
+	return w, w.parseCondition(conditionExpr, ctx)
+}
+
+func newConditionCheck(t *table, keyValues item, conditionExpr *string, ctx *exprContext) (*write, error) {
+	key, err := t.key(keyValues, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if conditionExpr == nil {
+		return nil, validationError("The ConditionExpression is required for a ConditionCheck")
+	}
+
+	w := &write{table: t, key: key}
+
+	return w, w.parseCondition(conditionExpr, ctx)
+}
+
+func (w *write) parseCondition(conditionExpr *string, ctx *exprContext) error {
+	var err error
+	w.condition, err = parseOptionalCondition("ConditionExpression", conditionExpr, ctx)
+
+	return err
+}
+
+// prepare checks the condition against the stored item and computes
+// the item after the write, without storing it. It returns the stored
+// item even when the condition fails.
+func (w *write) prepare() (old, updated item, err error) {
+	old = w.table.get(w.key)
+	ok, err := matches(w.condition, old)
+	if err != nil {
+		return old, nil, err
+	}
+
+	if !ok {
+		return old, nil, conditionalCheckFailed()
+	}
+
+	if w.change == nil {
+		return old, old, nil
+	}
+
+	updated, err = w.change(old)
+
+	return old, updated, err
+}
+
+// commit stores the item computed by prepare.
+func (w *write) commit(updated item) {
+	switch {
+	case w.change == nil:
+	case updated == nil:
+		w.table.remove(w.key)
+	default:
+		w.table.put(updated)
+	}
+}
+
+// run prepares and commits the write, returning the stored items from
+// before and after it.
+func (w *write) run() (old, updated item, err error) {
+	old, updated, err = w.prepare()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w.commit(updated)
+
+	return old, updated, nil
+}
+
+// GetItem reads the item with the key, with only the projected
+// attributes when there is a ProjectionExpression.
+func (e *Emulator) GetItem(
+	ctx context.Context,
+	input *dynamodb.GetItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.GetItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, err := e.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := t.key(input.Key, true)
+	if err != nil {
+		return nil, err
+	}
+
+	exprCtx := newExprContext(input.ExpressionAttributeNames, nil)
+	projection, err := parseOptionalProjection(input.ProjectionExpression, exprCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exprCtx.unused(); err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.GetItemOutput{Item: copyItem(project(t.get(key), projection))}, nil
+}
+
+// PutItem creates or replaces the item when it meets the
+// ConditionExpression.
+func (e *Emulator) PutItem(
+	ctx context.Context,
+	input *dynamodb.PutItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.PutItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := validateReturnValues(input.ReturnValues, types.ReturnValueNone, types.ReturnValueAllOld); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, err := e.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	exprCtx := newExprContext(input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	w, err := newPut(t, input.Item, input.ConditionExpression, exprCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exprCtx.unused(); err != nil {
+		return nil, err
+	}
+
+	old, _, err := w.run()
+	if err != nil {
+		return nil, err
+	}
+
+	output := &dynamodb.PutItemOutput{}
+	if input.ReturnValues == types.ReturnValueAllOld {
+		output.Attributes = copyItem(old)
+	}
+
+	return output, nil
+}
+
+// UpdateItem runs the UpdateExpression on the item when it meets the
+// ConditionExpression, creating the item when there is none.
+func (e *Emulator) UpdateItem(
+	ctx context.Context,
+	input *dynamodb.UpdateItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.UpdateItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	err := validateReturnValues(input.ReturnValues, types.ReturnValueNone, types.ReturnValueAllOld,
+		types.ReturnValueUpdatedOld, types.ReturnValueAllNew, types.ReturnValueUpdatedNew)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, err := e.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	exprCtx := newExprContext(input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	w, err := newUpdate(t, input.Key, input.UpdateExpression, input.ConditionExpression, exprCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exprCtx.unused(); err != nil {
+		return nil, err
+	}
+
+	old, updated, err := w.run()
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes item
+	switch input.ReturnValues {
+	case types.ReturnValueAllOld:
+		attributes = old
+	case types.ReturnValueUpdatedOld:
+		attributes = project(old, w.updated)
+	case types.ReturnValueAllNew:
+		attributes = updated
+	case types.ReturnValueUpdatedNew:
+		attributes = project(updated, w.updated)
+	}
+
+	if len(attributes) == 0 {
+		attributes = nil
+	}
+
+	return &dynamodb.UpdateItemOutput{Attributes: copyItem(attributes)}, nil
+}
+
+// DeleteItem deletes the item when it meets the ConditionExpression.
+// Deleting an item which does not exist succeeds.
+func (e *Emulator) DeleteItem(
+	ctx context.Context,
+	input *dynamodb.DeleteItemInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.DeleteItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := validateReturnValues(input.ReturnValues, types.ReturnValueNone, types.ReturnValueAllOld); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, err := e.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	exprCtx := newExprContext(input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	w, err := newDelete(t, input.Key, input.ConditionExpression, exprCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exprCtx.unused(); err != nil {
+		return nil, err
+	}
+
+	old, _, err := w.run()
+	if err != nil {
+		return nil, err
+	}
+
+	output := &dynamodb.DeleteItemOutput{}
+	if input.ReturnValues == types.ReturnValueAllOld {
+		output.Attributes = copyItem(old)
+	}
+
+	return output, nil
+}
+
+func validateReturnValues(returnValues types.ReturnValue, allowed ...types.ReturnValue) error {
+	if returnValues == "" {
+		return nil
+	}
+
+	for _, value := range allowed {
+		if returnValues == value {
+			return nil
+		}
+	}
+
+	return validationError("Return values set to invalid value")
+}
+
+func parseOptionalProjection(expr *string, ctx *exprContext) ([]path, error) {
+	if expr == nil {
+		return nil, nil
+	}
+
+	return parseProjection(*expr, ctx)
+}
+
+func parseOptionalCondition(kind string, expr *string, ctx *exprContext) (condition, error) {
+	if expr == nil {
+		return nil, nil
+	}
+
+	return parseCondition(kind, *expr, ctx)
+}
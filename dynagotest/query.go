@@ -0,0 +1,366 @@
+package dynagotest
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"hash/fnv"
+)
+
+// Query reads the partition of the KeyConditionExpression in the order
+// of its range key, a page at a time. Like DynamoDb, the FilterExpression
+// is evaluated after the Limit, so a page may hold fewer items than the
+// Limit while there are more to read.
+func (e *Emulator) Query(
+	ctx context.Context,
+	input *dynamodb.QueryInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.QueryOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if input.IndexName != nil {
+		return nil, validationError("The table does not have the specified index: %s", *input.IndexName)
+	}
+
+	if input.KeyConditionExpression == nil {
+		return nil, validationError("Either the KeyConditions or KeyConditionExpression parameter must be specified " +
+			"in the request.")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, err := e.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	exprCtx := newExprContext(input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	keyCondition, err := parseCondition("KeyConditionExpression", *input.KeyConditionExpression, exprCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := t.hashOf(keyCondition)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newReader(input.FilterExpression, input.ProjectionExpression, input.Select, input.Limit, exprCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exprCtx.unused(); err != nil {
+		return nil, err
+	}
+
+	forward := input.ScanIndexForward == nil || *input.ScanIndexForward
+
+	var start item
+	if input.ExclusiveStartKey != nil {
+		if start, err = t.key(input.ExclusiveStartKey, true); err != nil {
+			return nil, err
+		}
+
+		if !equal(start[t.hashKey], hash) {
+			return nil, validationError("The provided starting key is invalid: " +
+				"The provided key element does not match the schema")
+		}
+	}
+
+	var items []item
+	if p := t.partitions[keyString(hash)]; p != nil {
+		for i := range p.items {
+			stored := p.items[i]
+			if !forward {
+				stored = p.items[len(p.items)-1-i]
+			}
+
+			if start != nil {
+				c, _ := compare(stored[t.rangeKey], start[t.rangeKey])
+				if forward && c <= 0 || !forward && c >= 0 {
+					continue
+				}
+			}
+
+			ok, err := keyCondition.eval(stored)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				items = append(items, stored)
+			}
+		}
+	}
+
+	page, err := e.read(t, r, items)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.QueryOutput{
+		Items:            page.items,
+		Count:            page.count,
+		ScannedCount:     page.scannedCount,
+		LastEvaluatedKey: page.lastEvaluatedKey,
+	}, nil
+}
+
+// Scan reads all of the items of the table, or of the Segment, a page
+// at a time. The partitions are read in the order of their hash keys.
+func (e *Emulator) Scan(
+	ctx context.Context,
+	input *dynamodb.ScanInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.ScanOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if input.IndexName != nil {
+		return nil, validationError("The table does not have the specified index: %s", *input.IndexName)
+	}
+
+	if (input.Segment == nil) != (input.TotalSegments == nil) {
+		return nil, validationError("The TotalSegments parameter is required but was not present in the request " +
+			"when Segment parameter is present")
+	}
+
+	if input.TotalSegments != nil && (*input.TotalSegments < 1 || *input.Segment < 0 ||
+		*input.Segment >= *input.TotalSegments) {
+		return nil, validationError("The Segment parameter is zero-based and must be less than parameter " +
+			"TotalSegments")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	t, err := e.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	exprCtx := newExprContext(input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+	r, err := newReader(input.FilterExpression, input.ProjectionExpression, input.Select, input.Limit, exprCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := exprCtx.unused(); err != nil {
+		return nil, err
+	}
+
+	var start item
+	if input.ExclusiveStartKey != nil {
+		if start, err = t.key(input.ExclusiveStartKey, true); err != nil {
+			return nil, err
+		}
+	}
+
+	var items []item
+	for _, p := range t.sortedPartitions() {
+		if input.TotalSegments != nil && segment(p, *input.TotalSegments) != *input.Segment {
+			continue
+		}
+
+		for _, stored := range p.items {
+			if start == nil || t.after(stored, start) {
+				items = append(items, stored)
+			}
+		}
+	}
+
+	page, err := e.read(t, r, items)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.ScanOutput{
+		Items:            page.items,
+		Count:            page.count,
+		ScannedCount:     page.scannedCount,
+		LastEvaluatedKey: page.lastEvaluatedKey,
+	}, nil
+}
+
+// segment is the segment of a parallel Scan which reads the partition.
+func segment(p *partition, totalSegments int32) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(p.hash))
+
+	return int32(h.Sum32() % uint32(totalSegments))
+}
+
+// hashOf validates a KeyConditionExpression, which is an equality on the
+// hash key and, optionally, a condition on the range key joined by AND.
+// It returns the value of the hash key.
+func (t *table) hashOf(keyCondition condition) (types.AttributeValue, error) {
+	terms := []condition{keyCondition}
+	var hash types.AttributeValue
+	hasRange := false
+	for len(terms) > 0 {
+		term := terms[len(terms)-1]
+		terms = terms[:len(terms)-1]
+
+		var subject operand
+		var values []operand
+		isEquality := false
+		switch c := term.(type) {
+		case boolCondition:
+			if !c.and {
+				return nil, validationError("Invalid operator used in KeyConditionExpression: OR")
+			}
+
+			terms = append(terms, c.right, c.left)
+			continue
+		case compareCondition:
+			if c.op == "<>" {
+				return nil, validationError("Unsupported operator on KeyConditionExpression: operator: <>")
+			}
+
+			subject, values, isEquality = c.left, []operand{c.right}, c.op == "="
+		case betweenCondition:
+			subject, values = c.subject, []operand{c.lower, c.upper}
+		case funcCondition:
+			if c.name != "begins_with" {
+				return nil, validationError("Invalid operator used in KeyConditionExpression: %s", c.name)
+			}
+
+			subject, values = c.args[0], c.args[1:]
+		default:
+			return nil, validationError("Invalid operator used in KeyConditionExpression")
+		}
+
+		attribute, ok := subject.(pathOperand)
+		if !ok || len(attribute.path) != 1 {
+			return nil, validationError("Invalid KeyConditionExpression: The left-hand side of a key condition " +
+				"must be a key attribute")
+		}
+
+		name := attribute.path[0].name
+		if _, ok := t.keyTypes[name]; !ok {
+			return nil, validationError("Query condition missed key schema element: %s", t.hashKey)
+		}
+
+		for _, o := range values {
+			value, ok := o.(valueOperand)
+			if !ok {
+				return nil, validationError("Invalid KeyConditionExpression: The right-hand side of a key " +
+					"condition must be a value")
+			}
+
+			if typeName(value.attributeValue) != t.keyTypes[name] {
+				return nil, validationError("One or more parameter values were invalid: Condition parameter " +
+					"type does not match schema type")
+			}
+		}
+
+		switch {
+		case name == t.hashKey && hash == nil && isEquality:
+			hash = values[0].(valueOperand).attributeValue
+		case name == t.hashKey && hash == nil:
+			return nil, validationError("Query key condition not supported")
+		case name == t.rangeKey && !hasRange:
+			hasRange = true
+		default:
+			return nil, validationError("KeyConditionExpressions must only contain one condition per key")
+		}
+	}
+
+	if hash == nil {
+		return nil, validationError("Query condition missed key schema element: %s", t.hashKey)
+	}
+
+	return hash, nil
+}
+
+// reader is the part of a Query or Scan which reads the items.
+type reader struct {
+	filter     condition
+	projection []path
+	count      bool
+	limit      int
+}
+
+func newReader(filterExpr, projectionExpr *string, sel types.Select, limit *int32, ctx *exprContext) (*reader, error) {
+	r := &reader{}
+	if limit != nil {
+		if *limit < 1 {
+			return nil, validationError("1 validation error detected: Value '%d' at 'limit' failed to satisfy "+
+				"constraint: Member must have value greater than or equal to 1", *limit)
+		}
+
+		r.limit = int(*limit)
+	}
+
+	switch sel {
+	case "", types.SelectAllAttributes, types.SelectSpecificAttributes:
+	case types.SelectCount:
+		r.count = true
+	default:
+		return nil, validationError("ALL_PROJECTED_ATTRIBUTES can be used only when Querying using an IndexName")
+	}
+
+	var err error
+	if r.filter, err = parseOptionalCondition("FilterExpression", filterExpr, ctx); err != nil {
+		return nil, err
+	}
+
+	if r.projection, err = parseOptionalProjection(projectionExpr, ctx); err != nil {
+		return nil, err
+	}
+
+	if r.count && r.projection != nil {
+		return nil, validationError("Cannot specify the ProjectionExpression when choosing to get only the Count")
+	}
+
+	return r, nil
+}
+
+type page struct {
+	items            []item
+	count            int32
+	scannedCount     int32
+	lastEvaluatedKey item
+}
+
+// read reads a page of the items. Like DynamoDb, the page ends when the
+// Limit is met or a megabyte is read, and then has a LastEvaluatedKey,
+// even when there are no more items after it.
+func (e *Emulator) read(t *table, r *reader, items []item) (*page, error) {
+	limit := r.limit
+	if e.PageSize > 0 && (limit == 0 || e.PageSize < limit) {
+		limit = e.PageSize
+	}
+
+	p := &page{}
+	bytesRead := 0
+	for _, stored := range items {
+		p.scannedCount++
+		bytesRead += itemSize(stored)
+
+		ok, err := matches(r.filter, stored)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			p.count++
+			if !r.count {
+				p.items = append(p.items, copyItem(project(stored, r.projection)))
+			}
+		}
+
+		if int(p.scannedCount) == limit || bytesRead >= pageBytes {
+			key, _ := t.key(stored, false)
+			p.lastEvaluatedKey = copyItem(key)
+			break
+		}
+	}
+
+	return p, nil
+}
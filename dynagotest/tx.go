@@ -0,0 +1,331 @@
+package dynagotest
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"reflect"
+	"time"
+)
+
+const (
+	maxTransactItems = 100
+	// tokenExpiry is how long a ClientRequestToken identifies its
+	// transaction.
+	tokenExpiry = 10 * time.Minute
+)
+
+// transaction is a transaction which succeeded with a
+// ClientRequestToken, so that retrying it does not write again.
+type transaction struct {
+	items   []types.TransactWriteItem
+	expires time.Time
+}
+
+// TransactWriteItems writes all of the items or none of them. When any
+// condition fails, the transaction is canceled with a reason for each
+// of its items.
+func (e *Emulator) TransactWriteItems(
+	ctx context.Context,
+	input *dynamodb.TransactWriteItemsInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.TransactWriteItemsOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := validateTransactSize(len(input.TransactItems)); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	for token, tx := range e.transactions {
+		if now.After(tx.expires) {
+			delete(e.transactions, token)
+		}
+	}
+
+	if input.ClientRequestToken != nil {
+		if tx, ok := e.transactions[*input.ClientRequestToken]; ok {
+			if !reflect.DeepEqual(tx.items, input.TransactItems) {
+				return nil, idempotentParameterMismatch()
+			}
+
+			return &dynamodb.TransactWriteItemsOutput{}, nil
+		}
+	}
+
+	writes := make([]*write, len(input.TransactItems))
+	returnOld := make([]bool, len(input.TransactItems))
+	seen := make(targets)
+	for i, transactItem := range input.TransactItems {
+		var err error
+		writes[i], returnOld[i], err = e.transactWrite(transactItem)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := seen.add(writes[i].table, writes[i].key); err != nil {
+			return nil, err
+		}
+	}
+
+	updated := make([]item, len(writes))
+	reasons := make([]types.CancellationReason, len(writes))
+	canceled := false
+	for i, w := range writes {
+		var old item
+		var err error
+		old, updated[i], err = w.prepare()
+		reasons[i] = cancellationReason(err)
+		if err == nil {
+			continue
+		}
+
+		canceled = true
+		if returnOld[i] && *reasons[i].Code == "ConditionalCheckFailed" {
+			reasons[i].Item = copyItem(old)
+		}
+	}
+
+	if canceled {
+		return nil, transactionCanceled(reasons)
+	}
+
+	for i, w := range writes {
+		w.commit(updated[i])
+	}
+
+	if input.ClientRequestToken != nil {
+		e.transactions[*input.ClientRequestToken] = transaction{
+			items:   copyTransactItems(input.TransactItems),
+			expires: now.Add(tokenExpiry),
+		}
+	}
+
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+// copyTransactItems copies the items of a transaction, so that the
+// caller changing them does not change the stored transaction.
+func copyTransactItems(from []types.TransactWriteItem) []types.TransactWriteItem {
+	to := make([]types.TransactWriteItem, len(from))
+	for i, transactItem := range from {
+		if put := transactItem.Put; put != nil {
+			c := *put
+			c.Item, c.ExpressionAttributeValues = copyItem(put.Item), copyItem(put.ExpressionAttributeValues)
+			c.ExpressionAttributeNames = copyNames(put.ExpressionAttributeNames)
+			to[i].Put = &c
+		}
+
+		if update := transactItem.Update; update != nil {
+			c := *update
+			c.Key, c.ExpressionAttributeValues = copyItem(update.Key), copyItem(update.ExpressionAttributeValues)
+			c.ExpressionAttributeNames = copyNames(update.ExpressionAttributeNames)
+			to[i].Update = &c
+		}
+
+		if del := transactItem.Delete; del != nil {
+			c := *del
+			c.Key, c.ExpressionAttributeValues = copyItem(del.Key), copyItem(del.ExpressionAttributeValues)
+			c.ExpressionAttributeNames = copyNames(del.ExpressionAttributeNames)
+			to[i].Delete = &c
+		}
+
+		if check := transactItem.ConditionCheck; check != nil {
+			c := *check
+			c.Key, c.ExpressionAttributeValues = copyItem(check.Key), copyItem(check.ExpressionAttributeValues)
+			c.ExpressionAttributeNames = copyNames(check.ExpressionAttributeNames)
+			to[i].ConditionCheck = &c
+		}
+	}
+
+	return to
+}
+
+func copyNames(from map[string]string) map[string]string {
+	if from == nil {
+		return nil
+	}
+
+	to := make(map[string]string, len(from))
+	for placeholder, name := range from {
+		to[placeholder] = name
+	}
+
+	return to
+}
+
+// transactWrite validates an item of a transaction, which must have
+// exactly one operation. It also reports whether the operation returns
+// the stored item when its condition fails.
+func (e *Emulator) transactWrite(transactItem types.TransactWriteItem) (*write, bool, error) {
+	var tableName *string
+	var names map[string]string
+	var values map[string]types.AttributeValue
+	var returnValues types.ReturnValuesOnConditionCheckFailure
+	var newWrite func(*table, *exprContext) (*write, error)
+
+	operations := 0
+	if put := transactItem.Put; put != nil {
+		operations++
+		tableName, names, values, returnValues = put.TableName, put.ExpressionAttributeNames,
+			put.ExpressionAttributeValues, put.ReturnValuesOnConditionCheckFailure
+		newWrite = func(t *table, ctx *exprContext) (*write, error) {
+			return newPut(t, put.Item, put.ConditionExpression, ctx)
+		}
+	}
+
+	if update := transactItem.Update; update != nil {
+		operations++
+		tableName, names, values, returnValues = update.TableName, update.ExpressionAttributeNames,
+			update.ExpressionAttributeValues, update.ReturnValuesOnConditionCheckFailure
+		newWrite = func(t *table, ctx *exprContext) (*write, error) {
+			return newUpdate(t, update.Key, update.UpdateExpression, update.ConditionExpression, ctx)
+		}
+	}
+
+	if del := transactItem.Delete; del != nil {
+		operations++
+		tableName, names, values, returnValues = del.TableName, del.ExpressionAttributeNames,
+			del.ExpressionAttributeValues, del.ReturnValuesOnConditionCheckFailure
+		newWrite = func(t *table, ctx *exprContext) (*write, error) {
+			return newDelete(t, del.Key, del.ConditionExpression, ctx)
+		}
+	}
+
+	if check := transactItem.ConditionCheck; check != nil {
+		operations++
+		tableName, names, values, returnValues = check.TableName, check.ExpressionAttributeNames,
+			check.ExpressionAttributeValues, check.ReturnValuesOnConditionCheckFailure
+		newWrite = func(t *table, ctx *exprContext) (*write, error) {
+			return newConditionCheck(t, check.Key, check.ConditionExpression, ctx)
+		}
+	}
+
+	if operations != 1 {
+		return nil, false, validationError("TransactItems can only contain one of Check, Put, Update or Delete")
+	}
+
+	t, err := e.table(tableName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	exprCtx := newExprContext(names, values)
+	w, err := newWrite(t, exprCtx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return w, returnValues == types.ReturnValuesOnConditionCheckFailureAllOld, exprCtx.unused()
+}
+
+// cancellationReason is the reason a prepared write gives for canceling
+// its transaction, which has the code None when it did not fail.
+func cancellationReason(err error) types.CancellationReason {
+	var conditionFailed *types.ConditionalCheckFailedException
+	var apiErr smithy.APIError
+	switch {
+	case err == nil:
+		return types.CancellationReason{Code: aws.String("None")}
+	case errors.As(err, &conditionFailed):
+		return types.CancellationReason{Code: aws.String("ConditionalCheckFailed"), Message: conditionFailed.Message}
+	case errors.As(err, &apiErr):
+		return types.CancellationReason{Code: aws.String("ValidationError"), Message: aws.String(apiErr.ErrorMessage())}
+	default:
+		return types.CancellationReason{Code: aws.String("ValidationError"), Message: aws.String(err.Error())}
+	}
+}
+
+// TransactGetItems reads the items at once, in the order of the
+// TransactItems. The response of an item which does not exist has no
+// item.
+func (e *Emulator) TransactGetItems(
+	ctx context.Context,
+	input *dynamodb.TransactGetItemsInput,
+	_ ...func(*dynamodb.Options),
+) (*dynamodb.TransactGetItemsOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := validateTransactSize(len(input.TransactItems)); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	responses := make([]types.ItemResponse, len(input.TransactItems))
+	seen := make(targets)
+	for i, transactItem := range input.TransactItems {
+		get := transactItem.Get
+		if get == nil {
+			return nil, validationError("TransactItems must have a Get")
+		}
+
+		t, err := e.table(get.TableName)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := t.key(get.Key, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := seen.add(t, key); err != nil {
+			return nil, err
+		}
+
+		exprCtx := newExprContext(get.ExpressionAttributeNames, nil)
+		projection, err := parseOptionalProjection(get.ProjectionExpression, exprCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := exprCtx.unused(); err != nil {
+			return nil, err
+		}
+
+		responses[i].Item = copyItem(project(t.get(key), projection))
+	}
+
+	return &dynamodb.TransactGetItemsOutput{Responses: responses}, nil
+}
+
+// targets are the items of a transaction by table, which a transaction
+// may only operate on once each.
+type targets map[*table]map[string]bool
+
+func (ts targets) add(t *table, key item) error {
+	if ts[t] == nil {
+		ts[t] = make(map[string]bool)
+	}
+
+	id := t.keyID(key)
+	if ts[t][id] {
+		return validationError("Transaction request cannot include multiple operations on one item")
+	}
+
+	ts[t][id] = true
+
+	return nil
+}
+
+func validateTransactSize(items int) error {
+	if items < 1 || items > maxTransactItems {
+		return validationError("1 validation error detected: Value at 'transactItems' failed to satisfy "+
+			"constraint: Member must have length less than or equal to %d and greater than or equal to 1",
+			maxTransactItems)
+	}
+
+	return nil
+}
@@ -0,0 +1,432 @@
+package dynagotest
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"math/big"
+	"strings"
+)
+
+// item is a stored item, which is never shared with callers.
+type item = map[string]types.AttributeValue
+
+func copyItem(from item) item {
+	if from == nil {
+		return nil
+	}
+
+	to := make(item, len(from))
+	for name, value := range from {
+		to[name] = copyValue(value)
+	}
+
+	return to
+}
+
+func copyValue(value types.AttributeValue) types.AttributeValue {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return &types.AttributeValueMemberS{Value: v.Value}
+	case *types.AttributeValueMemberN:
+		return &types.AttributeValueMemberN{Value: v.Value}
+	case *types.AttributeValueMemberB:
+		return &types.AttributeValueMemberB{Value: append([]byte(nil), v.Value...)}
+	case *types.AttributeValueMemberBOOL:
+		return &types.AttributeValueMemberBOOL{Value: v.Value}
+	case *types.AttributeValueMemberNULL:
+		return &types.AttributeValueMemberNULL{Value: v.Value}
+	case *types.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: append([]string(nil), v.Value...)}
+	case *types.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: append([]string(nil), v.Value...)}
+	case *types.AttributeValueMemberBS:
+		binaries := make([][]byte, len(v.Value))
+		for i, b := range v.Value {
+			binaries[i] = append([]byte(nil), b...)
+		}
+
+		return &types.AttributeValueMemberBS{Value: binaries}
+	case *types.AttributeValueMemberL:
+		values := make([]types.AttributeValue, len(v.Value))
+		for i, elem := range v.Value {
+			values[i] = copyValue(elem)
+		}
+
+		return &types.AttributeValueMemberL{Value: values}
+	case *types.AttributeValueMemberM:
+		return &types.AttributeValueMemberM{Value: copyItem(v.Value)}
+	default:
+		return value
+	}
+}
+
+// typeName is the DynamoDb name of the type of the value, as used by
+// attribute_type and in attribute definitions.
+func typeName(value types.AttributeValue) string {
+	switch value.(type) {
+	case *types.AttributeValueMemberS:
+		return "S"
+	case *types.AttributeValueMemberN:
+		return "N"
+	case *types.AttributeValueMemberB:
+		return "B"
+	case *types.AttributeValueMemberBOOL:
+		return "BOOL"
+	case *types.AttributeValueMemberNULL:
+		return "NULL"
+	case *types.AttributeValueMemberSS:
+		return "SS"
+	case *types.AttributeValueMemberNS:
+		return "NS"
+	case *types.AttributeValueMemberBS:
+		return "BS"
+	case *types.AttributeValueMemberL:
+		return "L"
+	case *types.AttributeValueMemberM:
+		return "M"
+	default:
+		return ""
+	}
+}
+
+// validateValue rejects the values which DynamoDb does not store, such
+// as invalid numbers and empty or duplicate sets.
+func validateValue(value types.AttributeValue) error {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberN:
+		_, err := parseNumber(v.Value)
+		return err
+	case *types.AttributeValueMemberSS:
+		return validateSet("SS", v.Value, func(s string) (string, error) { return s, nil })
+	case *types.AttributeValueMemberNS:
+		return validateSet("NS", v.Value, func(n string) (string, error) {
+			r, err := parseNumber(n)
+			if err != nil {
+				return "", err
+			}
+
+			return formatNumber(r), nil
+		})
+	case *types.AttributeValueMemberBS:
+		members := make([]string, len(v.Value))
+		for i, b := range v.Value {
+			members[i] = string(b)
+		}
+
+		return validateSet("BS", members, func(b string) (string, error) { return b, nil })
+	case *types.AttributeValueMemberL:
+		for _, elem := range v.Value {
+			if err := validateValue(elem); err != nil {
+				return err
+			}
+		}
+	case *types.AttributeValueMemberM:
+		return validateItem(v.Value)
+	case nil:
+		return validationError("Supplied AttributeValue is empty, must contain exactly one of the supported datatypes")
+	}
+
+	return nil
+}
+
+func validateSet(setType string, members []string, canonical func(string) (string, error)) error {
+	if len(members) == 0 {
+		return validationError("One or more parameter values were invalid: An %s may not be empty", setType)
+	}
+
+	seen := make(map[string]bool)
+	for _, member := range members {
+		c, err := canonical(member)
+		if err != nil {
+			return err
+		}
+
+		if seen[c] {
+			return validationError("One or more parameter values were invalid: Input collection contains duplicates")
+		}
+
+		seen[c] = true
+	}
+
+	return nil
+}
+
+func validateItem(values item) error {
+	for _, value := range values {
+		if err := validateValue(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseNumber parses a DynamoDb number exactly.
+func parseNumber(n string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(n))
+	if !ok || strings.ContainsAny(n, "/") {
+		return nil, validationError("A value provided cannot be converted into a number")
+	}
+
+	return r, nil
+}
+
+// formatNumber formats the number in the shortest decimal notation,
+// which is the same for equal numbers.
+func formatNumber(r *big.Rat) string {
+	// Numbers are parsed from decimals, so their denominators divide a
+	// power of ten. Anything else, which no operation produces, is rounded.
+	digits := 0
+	denominator := new(big.Int).Set(r.Denom())
+	for _, factor := range []int64{2, 5} {
+		f := big.NewInt(factor)
+		count := 0
+		for new(big.Int).Mod(denominator, f).Sign() == 0 {
+			denominator.Div(denominator, f)
+			count++
+		}
+
+		if count > digits {
+			digits = count
+		}
+	}
+
+	if denominator.Cmp(big.NewInt(1)) != 0 {
+		digits = 38
+	}
+
+	s := r.FloatString(digits)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+
+	if s == "-0" {
+		return "0"
+	}
+
+	return s
+}
+
+// equal reports whether the values are equal the way DynamoDb compares
+// them, where numbers are compared by value and sets are unordered.
+func equal(a, b types.AttributeValue) bool {
+	switch x := a.(type) {
+	case *types.AttributeValueMemberN:
+		y, ok := b.(*types.AttributeValueMemberN)
+		if !ok {
+			return false
+		}
+
+		c, ok := compare(x, y)
+		return ok && c == 0
+	case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+		if typeName(a) != typeName(b) {
+			return false
+		}
+
+		xs, ys := setMembers(a), setMembers(b)
+		if len(xs) != len(ys) {
+			return false
+		}
+
+		for member := range xs {
+			if !ys[member] {
+				return false
+			}
+		}
+
+		return true
+	case *types.AttributeValueMemberL:
+		y, ok := b.(*types.AttributeValueMemberL)
+		if !ok || len(x.Value) != len(y.Value) {
+			return false
+		}
+
+		for i := range x.Value {
+			if !equal(x.Value[i], y.Value[i]) {
+				return false
+			}
+		}
+
+		return true
+	case *types.AttributeValueMemberM:
+		y, ok := b.(*types.AttributeValueMemberM)
+		if !ok || len(x.Value) != len(y.Value) {
+			return false
+		}
+
+		for name, value := range x.Value {
+			other, ok := y.Value[name]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+
+		return true
+	case *types.AttributeValueMemberS, *types.AttributeValueMemberB:
+		c, ok := compare(a, b)
+		return ok && c == 0
+	case *types.AttributeValueMemberBOOL:
+		y, ok := b.(*types.AttributeValueMemberBOOL)
+		return ok && x.Value == y.Value
+	case *types.AttributeValueMemberNULL:
+		_, ok := b.(*types.AttributeValueMemberNULL)
+		return ok
+	default:
+		return false
+	}
+}
+
+// compare orders values of the same scalar type: numbers by value and
+// strings and binaries by their bytes. It reports false for values
+// which cannot be ordered.
+func compare(a, b types.AttributeValue) (int, bool) {
+	switch x := a.(type) {
+	case *types.AttributeValueMemberN:
+		y, ok := b.(*types.AttributeValueMemberN)
+		if !ok {
+			return 0, false
+		}
+
+		rx, errX := parseNumber(x.Value)
+		ry, errY := parseNumber(y.Value)
+		if errX != nil || errY != nil {
+			return 0, false
+		}
+
+		return rx.Cmp(ry), true
+	case *types.AttributeValueMemberS:
+		y, ok := b.(*types.AttributeValueMemberS)
+		if !ok {
+			return 0, false
+		}
+
+		return strings.Compare(x.Value, y.Value), true
+	case *types.AttributeValueMemberB:
+		y, ok := b.(*types.AttributeValueMemberB)
+		if !ok {
+			return 0, false
+		}
+
+		return bytes.Compare(x.Value, y.Value), true
+	default:
+		return 0, false
+	}
+}
+
+// member is an element of a set, with its canonical form by which the
+// elements are compared.
+type member struct {
+	canonical string
+	raw       string
+}
+
+func members(set types.AttributeValue) []member {
+	var elems []member
+	switch s := set.(type) {
+	case *types.AttributeValueMemberSS:
+		for _, elem := range s.Value {
+			elems = append(elems, member{elem, elem})
+		}
+	case *types.AttributeValueMemberNS:
+		for _, elem := range s.Value {
+			canonical := elem
+			if r, err := parseNumber(elem); err == nil {
+				canonical = formatNumber(r)
+			}
+
+			elems = append(elems, member{canonical, elem})
+		}
+	case *types.AttributeValueMemberBS:
+		for _, elem := range s.Value {
+			elems = append(elems, member{string(elem), string(elem)})
+		}
+	}
+
+	return elems
+}
+
+// setMembers are the canonical members of a set.
+func setMembers(set types.AttributeValue) map[string]bool {
+	canonical := make(map[string]bool)
+	for _, m := range members(set) {
+		canonical[m.canonical] = true
+	}
+
+	return canonical
+}
+
+// keyString identifies a key attribute, so that equal keys have the
+// same string.
+func keyString(value types.AttributeValue) string {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return "S:" + v.Value
+	case *types.AttributeValueMemberN:
+		r, err := parseNumber(v.Value)
+		if err != nil {
+			return "N:" + v.Value
+		}
+
+		return "N:" + formatNumber(r)
+	case *types.AttributeValueMemberB:
+		return "B:" + string(v.Value)
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// size estimates the size of the value the way DynamoDb counts it
+// towards the one megabyte limit of a page.
+func size(value types.AttributeValue) int {
+	switch v := value.(type) {
+	case *types.AttributeValueMemberS:
+		return len(v.Value)
+	case *types.AttributeValueMemberN:
+		return (len(v.Value)+1)/2 + 1
+	case *types.AttributeValueMemberB:
+		return len(v.Value)
+	case *types.AttributeValueMemberSS:
+		total := 0
+		for _, s := range v.Value {
+			total += len(s)
+		}
+
+		return total
+	case *types.AttributeValueMemberNS:
+		total := 0
+		for _, n := range v.Value {
+			total += (len(n)+1)/2 + 1
+		}
+
+		return total
+	case *types.AttributeValueMemberBS:
+		total := 0
+		for _, b := range v.Value {
+			total += len(b)
+		}
+
+		return total
+	case *types.AttributeValueMemberL:
+		total := 3
+		for _, elem := range v.Value {
+			total += size(elem) + 1
+		}
+
+		return total
+	case *types.AttributeValueMemberM:
+		return 3 + itemSize(v.Value)
+	default:
+		return 1
+	}
+}
+
+func itemSize(values item) int {
+	total := 0
+	for name, value := range values {
+		total += len(name) + size(value)
+	}
+
+	return total
+}
@@ -0,0 +1,93 @@
+package dynago
+
+import "fmt"
+
+// ItemNotFoundError is returned when an item looked up by its
+// primary key does not exist in the Table.
+//
+//  _, err := table.Get(Person{Id: "abc"})
+//  var notFound *dynago.ItemNotFoundError
+//  if errors.As(err, &notFound) {
+//    // Handle the missing item
+//  }
+type ItemNotFoundError struct {
+	TableName string
+	Key       interface{}
+}
+
+func (e *ItemNotFoundError) Error() string {
+	return fmt.Sprintf("item %v not found in table %s", e.Key, e.TableName)
+}
+
+// BatchWriteError is returned when some writes of a batch operation,
+// such as Table.PutAll, failed. Every other write succeeded.
+type BatchWriteError struct {
+	Failures []BatchFailure
+}
+
+// BatchFailure is a single failed write of a batch operation.
+// Index is the position of the Item in the batch.
+type BatchFailure struct {
+	Index int
+	Item  interface{}
+	Err   error
+}
+
+func (e *BatchWriteError) Error() string {
+	return fmt.Sprintf("%d batch writes failed, the first with: %v", len(e.Failures), e.Failures[0].Err)
+}
+
+// MissingItemsError is returned by Table.GetMany when some of the keys
+// have no item in the Table. Indexes are the positions of the Keys
+// in the request.
+type MissingItemsError struct {
+	TableName string
+	Indexes   []int
+	Keys      []interface{}
+}
+
+func (e *MissingItemsError) Error() string {
+	return fmt.Sprintf("%d items not found in table %s: %v", len(e.Keys), e.TableName, e.Keys)
+}
+
+// TxCanceledError is returned when DynamoDb cancels a transaction.
+// Reasons only holds the operations which caused the cancellation.
+//
+//  var canceled *dynago.TxCanceledError
+//  if errors.As(err, &canceled) {
+//    for _, reason := range canceled.Reasons {
+//      if reason.Code == dynago.ReasonConditionalCheckFailed {
+//        log.Printf("operation %d failed its condition on %v", reason.Index, reason.Item)
+//      }
+//    }
+//  }
+type TxCanceledError struct {
+	Message string
+	Reasons []TxCancellationReason
+}
+
+// TxCancellationReason describes why a single operation canceled a
+// transaction. Index is the position of the operation in the transaction.
+//
+// When a Condition was not met, Item is the item as it was before the
+// transaction, decoded with the schema of the operation's Table.
+type TxCancellationReason struct {
+	Index   int
+	Code    string
+	Message string
+	Item    interface{}
+}
+
+// Codes of a TxCancellationReason.
+const (
+	ReasonConditionalCheckFailed        = "ConditionalCheckFailed"
+	ReasonTransactionConflict           = "TransactionConflict"
+	ReasonItemCollectionSizeLimit       = "ItemCollectionSizeLimitExceeded"
+	ReasonProvisionedThroughputExceeded = "ProvisionedThroughputExceeded"
+	ReasonThrottlingError               = "ThrottlingError"
+	ReasonValidationError               = "ValidationError"
+)
+
+func (e *TxCanceledError) Error() string {
+	return fmt.Sprintf("transaction canceled: %s", e.Message)
+}
@@ -0,0 +1,99 @@
+package dynago
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
+	"strings"
+)
+
+// exprBuilder allocates the placeholders used by expressions.
+// Every attribute name is replaced by a #n placeholder, so reserved
+// words can be used as names, and every value gets its own :v placeholder,
+// so values never overwrite each other.
+//
+// A single exprBuilder is shared by all expressions of a request,
+// such as the update and condition expressions of an UpdateItem.
+type exprBuilder struct {
+	names        map[string]string
+	placeholders map[string]string
+	values       map[string]interface{}
+}
+
+func newExprBuilder() *exprBuilder {
+	return &exprBuilder{
+		names:        make(map[string]string),
+		placeholders: make(map[string]string),
+		values:       make(map[string]interface{}),
+	}
+}
+
+// path renders the AttributePath with placeholders for its names.
+func (b *exprBuilder) path(path AttributePath) string {
+	var expr strings.Builder
+	for i, element := range path.elements() {
+		switch {
+		case element.isIndex:
+			expr.WriteString("[" + strconv.Itoa(element.index) + "]")
+		case i > 0:
+			expr.WriteString("." + b.name(element.name))
+		default:
+			expr.WriteString(b.name(element.name))
+		}
+	}
+
+	return expr.String()
+}
+
+// name returns the placeholder of the attribute name.
+// The same name always has the same placeholder.
+func (b *exprBuilder) name(name string) string {
+	if placeholder, ok := b.placeholders[name]; ok {
+		return placeholder
+	}
+
+	placeholder := "#n" + strconv.Itoa(len(b.names))
+	b.names[placeholder] = name
+	b.placeholders[name] = placeholder
+
+	return placeholder
+}
+
+// value returns a new placeholder bound to the raw value.
+func (b *exprBuilder) value(raw interface{}) string {
+	placeholder := ":v" + strconv.Itoa(len(b.values))
+	b.values[placeholder] = raw
+
+	return placeholder
+}
+
+// projection replaces the names of a comma separated projection of
+// AttributePaths with placeholders. An empty projection projects
+// nothing away.
+func (b *exprBuilder) projection(projection string) *string {
+	if projection == "" {
+		return nil
+	}
+
+	var paths []string
+	for _, path := range splitProjection(projection) {
+		paths = append(paths, b.path(path))
+	}
+
+	expr := strings.Join(paths, ",")
+
+	return &expr
+}
+
+// attributeNames are the ExpressionAttributeNames of the request.
+func (b *exprBuilder) attributeNames() map[string]string {
+	if len(b.names) == 0 {
+		return nil
+	}
+
+	return b.names
+}
+
+// attributeValues are the ExpressionAttributeValues of the request.
+func (b *exprBuilder) attributeValues() (map[string]types.AttributeValue, error) {
+	return fromMap(b.values)
+}
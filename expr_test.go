@@ -0,0 +1,25 @@
+package dynago
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_exprBuilder_projection(t *testing.T) {
+	b := newExprBuilder()
+	condition := Eq("Name", S("abc")).buildExpr(b)
+	projection := b.projection("Id, Name,Status")
+
+	if *condition != "#n0 = :v0" || *projection != "#n1,#n0,#n2" {
+		t.Errorf("projection() = %v, condition = %v", *projection, *condition)
+	}
+
+	want := map[string]string{"#n0": "Name", "#n1": "Id", "#n2": "Status"}
+	if !reflect.DeepEqual(b.attributeNames(), want) {
+		t.Errorf("attributeNames() = %v, want %v", b.attributeNames(), want)
+	}
+
+	if newExprBuilder().projection("") != nil {
+		t.Errorf("projection() of nothing is not nil")
+	}
+}
@@ -1,9 +1,18 @@
 module github.com/eyebrow-fish/dynago
 
-go 1.14
+go 1.18
 
 require (
 	github.com/aws/aws-sdk-go-v2 v1.6.0
 	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.3.1
+	github.com/aws/smithy-go v1.4.0
 	github.com/stretchr/testify v1.7.0
 )
+
+require (
+	github.com/aws/aws-sdk-go-v2/service/internal/accept-encoding v1.1.0 // indirect
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
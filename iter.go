@@ -0,0 +1,229 @@
+package dynago
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"sync"
+)
+
+// Iter iterates over the items of a query or scan. It requests a page
+// of items only once the items before it have been read, and decodes
+// the items a page at a time, so that large results are never held in
+// memory at once and reading can stop after any item.
+//
+//  iter := table.QueryIter(dynago.Eq("Id", dynago.N(123)))
+//  defer iter.Close()
+//
+//  for iter.Next() {
+//    data := iter.Item().(MySchema)
+//  }
+//
+//  if err := iter.Err(); err != nil {
+//    panic(err)
+//  }
+//
+// The Iter yields at most as many items as the limit of the Condition.
+type Iter struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed chan struct{}
+	once   sync.Once
+
+	fetch   fetchPage
+	schema  interface{}
+	limit   *int32
+	lastKey map[string]types.AttributeValue
+	done    bool
+
+	page  []interface{}
+	item  interface{}
+	count int32
+	err   error
+}
+
+// fetchPage requests the page of items after lastKey, returning the
+// LastEvaluatedKey of the page.
+type fetchPage func(
+	ctx context.Context,
+	lastKey map[string]types.AttributeValue,
+	limit *int32,
+) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error)
+
+func newIter(ctx context.Context, schema interface{}, limit *int32, fetch fetchPage) *Iter {
+	it := &Iter{closed: make(chan struct{}), fetch: fetch, schema: schema, limit: limit}
+	it.ctx, it.cancel = context.WithCancel(ctx)
+
+	return it
+}
+
+// errIter is an Iter without any items, which fails with err.
+func errIter(err error) *Iter {
+	it := newIter(context.Background(), nil, nil, nil)
+	it.err, it.done = err, true
+
+	return it
+}
+
+// Next advances to the next item, requesting the next page when the
+// items of the current page have been read. It returns false once there
+// are no more items, the Iter is closed or a request fails.
+func (it *Iter) Next() bool {
+	if it.isClosed() {
+		it.item = nil
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			it.item = nil
+			it.cancel()
+
+			return false
+		}
+
+		it.nextPage()
+	}
+
+	it.item, it.page = it.page[0], it.page[1:]
+	it.count++
+
+	if it.limit != nil && it.count >= *it.limit {
+		it.done, it.page = true, nil
+	}
+
+	return true
+}
+
+// Item is the item Next advanced to, whose type is the same as the
+// Schema of the Table.
+func (it *Iter) Item() interface{} { return it.item }
+
+// Err is the error which ended the iteration, if any. Once the context
+// of the Iter is done, no more pages are requested and Err is the
+// context's error.
+func (it *Iter) Err() error { return it.err }
+
+// Close stops the iteration, canceling a request for a page which is
+// in progress. It is safe to call more than once and from another
+// goroutine, such as while the items are read from Chan.
+func (it *Iter) Close() {
+	it.once.Do(func() {
+		close(it.closed)
+		it.cancel()
+	})
+}
+
+// Chan sends the items of the Iter on a channel instead, which is
+// closed once there are no more items. Closing the Iter stops the
+// sending, and Err is to be checked once the channel is closed.
+//
+//  iter := table.ScanIter(dynago.All())
+//  defer iter.Close()
+//
+//  for item := range iter.Chan() {
+//    data := item.(MySchema)
+//  }
+//
+// Chan takes over the Iter, so Next and Item may no longer be used.
+func (it *Iter) Chan() <-chan interface{} {
+	items := make(chan interface{})
+	go func() {
+		defer close(items)
+
+		for it.Next() {
+			select {
+			case items <- it.Item():
+			case <-it.closed:
+				return
+			}
+		}
+	}()
+
+	return items
+}
+
+func (it *Iter) isClosed() bool {
+	select {
+	case <-it.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (it *Iter) nextPage() {
+	limit := it.limit
+	if limit != nil {
+		remaining := *limit - it.count
+		limit = &remaining
+	}
+
+	err := it.ctx.Err()
+	var items []map[string]types.AttributeValue
+	if err == nil {
+		items, it.lastKey, err = it.fetch(it.ctx, it.lastKey, limit)
+	}
+
+	if err != nil {
+		// The request of a closed Iter fails only because it is canceled.
+		if !it.isClosed() {
+			it.err = err
+		}
+
+		it.done = true
+
+		return
+	}
+
+	// Without a LastEvaluatedKey this was the last page, even when it
+	// has fewer items than the limit.
+	it.done = len(it.lastKey) == 0
+	it.page, it.err = constructItems(items, it.schema)
+}
+
+// all reads all of the remaining items and closes the Iter.
+func (it *Iter) all() ([]interface{}, error) {
+	defer it.Close()
+
+	var items []interface{}
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+
+	if it.err != nil {
+		return nil, it.err
+	}
+
+	return items, nil
+}
+
+// TypedIter is an Iter whose items are of type T, as read from a
+// TypedTable.
+type TypedIter[T any] struct{ *Iter }
+
+// Item is the item Next advanced to.
+func (it TypedIter[T]) Item() T {
+	item, _ := it.Iter.Item().(T)
+
+	return item
+}
+
+// Chan is the same as Iter.Chan, sending the items as T.
+func (it TypedIter[T]) Chan() <-chan T {
+	untyped := it.Iter.Chan()
+	items := make(chan T)
+	go func() {
+		defer close(items)
+
+		for item := range untyped {
+			typed, _ := item.(T)
+			select {
+			case items <- typed:
+			case <-it.closed:
+				return
+			}
+		}
+	}()
+
+	return items
+}
@@ -0,0 +1,95 @@
+package dynago
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"testing"
+)
+
+func newPagedTable(t *testing.T) (*Table, *pagedAPI) {
+	api := &pagedAPI{pages: [][]map[string]types.AttributeValue{
+		{testItem(map[string]interface{}{"Id": 1})},
+		{testItem(map[string]interface{}{"Id": 2}), testItem(map[string]interface{}{"Id": 3})},
+	}}
+
+	table, err := NewClientWithAPI(api).NewTable("Test", struct{ Id int }{})
+	if err != nil {
+		t.Fatalf("NewTable() err = %v", err)
+	}
+
+	return table, api
+}
+
+func TestIter_Close(t *testing.T) {
+	table, api := newPagedTable(t)
+
+	iter := table.ScanIter(All())
+	if !iter.Next() || iter.Item() != (struct{ Id int }{1}) {
+		t.Fatalf("Next() did not advance to the first item, Item() = %v", iter.Item())
+	}
+
+	iter.Close()
+	if iter.Next() || iter.Err() != nil {
+		t.Errorf("Next() after Close() advanced to %v, Err() = %v", iter.Item(), iter.Err())
+	}
+
+	if api.requests != 1 {
+		t.Errorf("ScanIter() made %d requests, want 1", api.requests)
+	}
+}
+
+func TestIter_limit(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int32
+		want     []interface{}
+		requests int
+	}{
+		{"first page", 1, []interface{}{struct{ Id int }{1}}, 1},
+		{"within last page", 2, []interface{}{struct{ Id int }{1}, struct{ Id int }{2}}, 2},
+		{
+			"beyond last page",
+			5,
+			[]interface{}{struct{ Id int }{1}, struct{ Id int }{2}, struct{ Id int }{3}},
+			2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, api := newPagedTable(t)
+
+			items, err := table.Scan(All().WithLimit(tt.limit))
+			if err != nil {
+				t.Fatalf("Scan() err = %v", err)
+			}
+
+			if !reflect.DeepEqual(items, tt.want) || api.requests != tt.requests {
+				t.Errorf("Scan() = %v after %d requests, want %v after %d", items, api.requests, tt.want, tt.requests)
+			}
+		})
+	}
+}
+
+func TestIter_Chan(t *testing.T) {
+	table, _ := newPagedTable(t)
+
+	var items []struct{ Id int }
+	for item := range TypedTableOf[struct{ Id int }](table).ScanIter(All()).Chan() {
+		items = append(items, item)
+	}
+
+	want := []struct{ Id int }{{1}, {2}, {3}}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("Chan() = %v, want %v", items, want)
+	}
+}
+
+func TestIter_Err(t *testing.T) {
+	table, api := newPagedTable(t)
+
+	iter := table.QueryIter(Gt("Id", N(1)))
+	if iter.Next() || iter.Err() == nil || api.requests != 0 {
+		t.Errorf("QueryIter() without a key condition advanced, Err() = %v", iter.Err())
+	}
+}
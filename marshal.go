@@ -0,0 +1,162 @@
+package dynago
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"sync"
+)
+
+// AttributeMarshaler is implemented by types which encode themselves
+// as an AttributeValue, taking precedence over the built-in encoding.
+//
+//  type Status int
+//
+//  func (s Status) MarshalAttribute() (types.AttributeValue, error) {
+//    return &types.AttributeValueMemberS{Value: s.String()}, nil
+//  }
+type AttributeMarshaler interface {
+	MarshalAttribute() (types.AttributeValue, error)
+}
+
+// AttributeUnmarshaler is implemented by types which decode themselves
+// from an AttributeValue. It is the reverse of AttributeMarshaler and
+// is implemented on a pointer receiver.
+//
+// NULL attributes decode into the zero value without calling
+// UnmarshalAttribute.
+type AttributeUnmarshaler interface {
+	UnmarshalAttribute(attribute types.AttributeValue) error
+}
+
+// AttributeCodec encodes and decodes values of a type which cannot
+// implement AttributeMarshaler and AttributeUnmarshaler itself, such
+// as a type from another package. Either function may be nil, in which
+// case the built-in encoding or decoding is used.
+type AttributeCodec struct {
+	Marshal   func(value interface{}) (types.AttributeValue, error)
+	Unmarshal func(attribute types.AttributeValue) (interface{}, error)
+}
+
+var codecs sync.Map // reflect.Type -> AttributeCodec
+
+// RegisterCodec registers the AttributeCodec for the type of value.
+// Registered codecs take precedence over the methods of the type.
+// Codecs are best registered before any items are encoded or decoded,
+// such as in an init function.
+//
+//  dynago.RegisterCodec(decimal.Decimal{}, dynago.AttributeCodec{
+//    Marshal: func(value interface{}) (types.AttributeValue, error) {
+//      return &types.AttributeValueMemberN{Value: value.(decimal.Decimal).String()}, nil
+//    },
+//    Unmarshal: func(attribute types.AttributeValue) (interface{}, error) {
+//      return decimal.NewFromString(attribute.(*types.AttributeValueMemberN).Value)
+//    },
+//  })
+func RegisterCodec(value interface{}, codec AttributeCodec) {
+	codecs.Store(reflect.TypeOf(value), codec)
+	resetCodecs()
+}
+
+var (
+	attributeMarshalerType   = reflect.TypeOf((*AttributeMarshaler)(nil)).Elem()
+	attributeUnmarshalerType = reflect.TypeOf((*AttributeUnmarshaler)(nil)).Elem()
+	textMarshalerType        = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType      = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// marshalCustom encodes the value with its registered AttributeCodec
+// or its AttributeMarshaler, in that order. It reports false when the
+// value has neither.
+func marshalCustom(value reflect.Value) (types.AttributeValue, bool, error) {
+	if codec, ok := codecs.Load(value.Type()); ok && codec.(AttributeCodec).Marshal != nil {
+		attributeValue, err := codec.(AttributeCodec).Marshal(value.Interface())
+
+		return attributeValue, true, err
+	}
+
+	if marshaler, ok := implements(value, attributeMarshalerType); ok {
+		attributeValue, err := marshaler.(AttributeMarshaler).MarshalAttribute()
+		if err == nil && attributeValue == nil {
+			attributeValue = &types.AttributeValueMemberNULL{Value: true}
+		}
+
+		return attributeValue, true, err
+	}
+
+	return nil, false, nil
+}
+
+// marshalText encodes the value with its encoding.TextMarshaler as a
+// string. It reports false when the value has none.
+func marshalText(value reflect.Value) (types.AttributeValue, bool, error) {
+	if marshaler, ok := implements(value, textMarshalerType); ok {
+		text, err := marshaler.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, true, err
+		}
+
+		return &types.AttributeValueMemberS{Value: string(text)}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// unmarshalCustom is the reverse of marshalCustom. The value must be
+// settable.
+func unmarshalCustom(attribute types.AttributeValue, value reflect.Value) (bool, error) {
+	if codec, ok := codecs.Load(value.Type()); ok && codec.(AttributeCodec).Unmarshal != nil {
+		decoded, err := codec.(AttributeCodec).Unmarshal(attribute)
+		if err != nil {
+			return true, err
+		}
+
+		decodedValue := reflect.ValueOf(decoded)
+		if !decodedValue.IsValid() || !decodedValue.Type().AssignableTo(value.Type()) {
+			return true, fmt.Errorf("codec of %v decoded %T", value.Type(), decoded)
+		}
+
+		value.Set(decodedValue)
+
+		return true, nil
+	}
+
+	if unmarshaler, ok := implements(value, attributeUnmarshalerType); ok {
+		return true, unmarshaler.(AttributeUnmarshaler).UnmarshalAttribute(attribute)
+	}
+
+	return false, nil
+}
+
+// unmarshalText is the reverse of marshalText.
+func unmarshalText(attribute types.AttributeValue, value reflect.Value) (bool, error) {
+	if unmarshaler, ok := implements(value, textUnmarshalerType); ok {
+		s, ok := attribute.(*types.AttributeValueMemberS)
+		if !ok {
+			return true, decodeMismatch(attribute, value)
+		}
+
+		return true, unmarshaler.(encoding.TextUnmarshaler).UnmarshalText([]byte(s.Value))
+	}
+
+	return false, nil
+}
+
+// implements returns the value, or a pointer to it when it is
+// addressable, as the interface it implements.
+func implements(value reflect.Value, iface reflect.Type) (interface{}, bool) {
+	if value.Kind() != reflect.Ptr && value.CanAddr() && value.Addr().Type().Implements(iface) {
+		return value.Addr().Interface(), true
+	}
+
+	if value.Type().Implements(iface) && value.CanInterface() {
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil, false
+		}
+
+		return value.Interface(), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,121 @@
+package dynago
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+)
+
+type testStatus int
+
+func (s testStatus) MarshalAttribute() (types.AttributeValue, error) {
+	return &types.AttributeValueMemberS{Value: []string{"active", "inactive"}[s]}, nil
+}
+
+func (s *testStatus) UnmarshalAttribute(attribute types.AttributeValue) error {
+	value, ok := attribute.(*types.AttributeValueMemberS)
+	if !ok {
+		return errors.New("expected a string")
+	}
+
+	switch value.Value {
+	case "active":
+		*s = 0
+	case "inactive":
+		*s = 1
+	default:
+		return errors.New("unknown status")
+	}
+
+	return nil
+}
+
+type testForeign struct{ value string }
+
+func Test_marshalers(t *testing.T) {
+	RegisterCodec(testForeign{}, AttributeCodec{
+		Marshal: func(value interface{}) (types.AttributeValue, error) {
+			return &types.AttributeValueMemberS{Value: value.(testForeign).value}, nil
+		},
+		Unmarshal: func(attribute types.AttributeValue) (interface{}, error) {
+			return testForeign{attribute.(*types.AttributeValueMemberS).Value}, nil
+		},
+	})
+
+	type custom struct {
+		Status   testStatus
+		Previous *testStatus
+		Foreign  testForeign
+		Amount   *big.Int
+		Address  net.IP
+	}
+
+	inactive := testStatus(1)
+	want := custom{
+		Status:   1,
+		Previous: &inactive,
+		Foreign:  testForeign{"abc"},
+		Amount:   big.NewInt(42),
+		Address:  net.ParseIP("10.0.0.1"),
+	}
+
+	item, err := buildItem(want)
+	if err != nil {
+		t.Fatalf("buildItem() error = %v", err)
+	}
+
+	wantItem := map[string]types.AttributeValue{
+		"Status":   &types.AttributeValueMemberS{Value: "inactive"},
+		"Previous": &types.AttributeValueMemberS{Value: "inactive"},
+		"Foreign":  &types.AttributeValueMemberS{Value: "abc"},
+		"Amount":   &types.AttributeValueMemberS{Value: "42"},
+		"Address":  &types.AttributeValueMemberS{Value: "10.0.0.1"},
+	}
+	if !reflect.DeepEqual(item, wantItem) {
+		t.Errorf("buildItem() = %v, want %v", item, wantItem)
+	}
+
+	got, err := constructItem(item, custom{})
+	if err != nil {
+		t.Fatalf("constructItem() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("constructItem() = %v, want %v", got, want)
+	}
+
+	item["Status"] = &types.AttributeValueMemberS{Value: "unknown"}
+	if _, err := constructItem(item, custom{}); err == nil {
+		t.Errorf("constructItem() error = nil, want unknown status")
+	}
+}
+
+func Test_toAttributeType(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    types.ScalarAttributeType
+		wantErr bool
+	}{
+		{"string", "", types.ScalarAttributeTypeS, false},
+		{"pointer", new(uint8), types.ScalarAttributeTypeN, false},
+		{"bytes", []byte(nil), types.ScalarAttributeTypeB, false},
+		{"marshaler", testStatus(0), types.ScalarAttributeTypeS, false},
+		{"map", map[string]string{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toAttributeType(schemaField{}, reflect.TypeOf(tt.value))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("toAttributeType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("toAttributeType() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package dynago
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Number is a DynamoDb number kept in its string form, so no precision
+// is lost for numbers beyond what float64 and the integer types hold.
+// DynamoDb numbers have up to 38 digits of precision.
+//
+//  type Measurement struct {
+//    Id    string
+//    Value dynago.Number
+//  }
+//
+// The zero value is stored as 0.
+type Number string
+
+// String returns the number as it is stored.
+func (n Number) String() string { return string(n) }
+
+// Int64 returns the number as an int64.
+func (n Number) Int64() (int64, error) { return parseInt(n.String(), 64) }
+
+// Uint64 returns the number as an uint64.
+func (n Number) Uint64() (uint64, error) { return parseUint(n.String(), 64) }
+
+// Float64 returns the number as a float64.
+func (n Number) Float64() (float64, error) { return parseFloat(n.String(), 64) }
+
+// BigFloat returns the number as a big.Float.
+func (n Number) BigFloat() (*big.Float, error) {
+	f, ok := new(big.Float).SetPrec(128).SetString(n.String())
+	if !ok {
+		return nil, fmt.Errorf("invalid number: %q", n)
+	}
+
+	return f, nil
+}
+
+// formatNumber validates the number and returns it in the form in
+// which it is stored.
+func formatNumber(n Number) (string, error) {
+	if n == "" {
+		return "0", nil
+	}
+
+	if _, err := n.BigFloat(); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(n.String()), nil
+}
+
+// parseInt parses the number as an integer of the given bit size.
+// Numbers in exponent or decimal notation are accepted as long as
+// they are whole numbers.
+func parseInt(s string, bits int) (int64, error) {
+	i, err := strconv.ParseInt(s, 10, bits)
+	if !isSyntaxError(err) {
+		return i, rangeError(s, err)
+	}
+
+	whole, err := parseWhole(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if !whole.IsInt64() {
+		return 0, rangeError(s, strconv.ErrRange)
+	}
+
+	return strconv.ParseInt(whole.String(), 10, bits)
+}
+
+// parseUint is the same as parseInt for unsigned integers.
+func parseUint(s string, bits int) (uint64, error) {
+	u, err := strconv.ParseUint(s, 10, bits)
+	if !isSyntaxError(err) {
+		return u, rangeError(s, err)
+	}
+
+	whole, err := parseWhole(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if !whole.IsUint64() {
+		return 0, rangeError(s, strconv.ErrRange)
+	}
+
+	return strconv.ParseUint(whole.String(), 10, bits)
+}
+
+func parseFloat(s string, bits int) (float64, error) {
+	f, err := strconv.ParseFloat(s, bits)
+
+	return f, rangeError(s, err)
+}
+
+// parseWhole parses a number which is not in plain integer notation,
+// such as 1E3, as a big.Int.
+func parseWhole(s string) (*big.Int, error) {
+	f, err := Number(s).BigFloat()
+	if err != nil {
+		return nil, err
+	}
+
+	if !f.IsInt() {
+		return nil, fmt.Errorf("%s is not an integer", s)
+	}
+
+	whole, _ := f.Int(nil)
+
+	return whole, nil
+}
+
+func isSyntaxError(err error) bool {
+	numErr, ok := err.(*strconv.NumError)
+
+	return ok && numErr.Err == strconv.ErrSyntax
+}
+
+// rangeError replaces the errors of strconv with a clearer one when
+// the number does not fit.
+func rangeError(s string, err error) error {
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		return fmt.Errorf("%s is out of range", s)
+	}
+
+	return err
+}
+
+// parseNumber parses the number into the most fitting Go type for an
+// empty interface: int for integers, float64 for other numbers and
+// Number for numbers which neither can hold.
+func parseNumber(s string) (interface{}, error) {
+	if i, err := strconv.Atoi(s); err == nil {
+		return i, nil
+	}
+
+	if _, err := Number(s).BigFloat(); err != nil {
+		return nil, err
+	}
+
+	if !strings.ContainsAny(s, ".eE") {
+		return Number(s), nil // An integer beyond int
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Number(s), nil
+	}
+
+	return f, nil
+}
@@ -0,0 +1,130 @@
+package dynago
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AttributePath refers to an attribute of an item, which can be nested
+// inside of maps and lists. It is accepted anywhere a field name is.
+//
+// An AttributePath is written as a DynamoDb document path, where map
+// fields are separated by dots and list elements are indexed with
+// brackets. Path builds them while escaping the names, which is needed
+// when names contain any of . [ ] , or \.
+//
+//  dynago.Eq("Address.City", dynago.S("Amsterdam"))
+//  dynago.Eq(dynago.Path("Address", "City"), dynago.S("Amsterdam"))
+//  dynago.Remove(dynago.Path("Tags").Index(0))
+type AttributePath string
+
+// pathLike is a field name or an AttributePath, so that Conditions and
+// Updates can be given either, including names held in string variables.
+type pathLike interface{ ~string }
+
+// Path builds an AttributePath out of nested map field names.
+func Path(names ...string) AttributePath {
+	var escaped []string
+	for _, name := range names {
+		escaped = append(escaped, escapePathName(name))
+	}
+
+	return AttributePath(strings.Join(escaped, "."))
+}
+
+// Field refers to the field of the map at this path.
+func (p AttributePath) Field(name string) AttributePath {
+	return p + "." + AttributePath(escapePathName(name))
+}
+
+// Index refers to the element of the list at this path.
+func (p AttributePath) Index(index int) AttributePath {
+	return p + "[" + AttributePath(strconv.Itoa(index)) + "]"
+}
+
+func escapePathName(name string) string {
+	var escaped strings.Builder
+	for _, r := range name {
+		switch r {
+		case '.', '[', ']', ',', '\\':
+			escaped.WriteRune('\\')
+		}
+
+		escaped.WriteRune(r)
+	}
+
+	return escaped.String()
+}
+
+// pathElement is either a map field name or a list index.
+type pathElement struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// elements parses the AttributePath. Brackets which do not hold an
+// index are read as part of the name.
+func (p AttributePath) elements() []pathElement {
+	var elements []pathElement
+	var name strings.Builder
+	flush := func() {
+		if name.Len() > 0 {
+			elements = append(elements, pathElement{name: name.String()})
+			name.Reset()
+		}
+	}
+
+	path := string(p)
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '\\':
+			if i+1 < len(path) {
+				i++
+			}
+
+			name.WriteByte(path[i])
+		case '.':
+			flush()
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				name.WriteByte(c)
+				continue
+			}
+
+			index, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil || index < 0 {
+				name.WriteByte(c)
+				continue
+			}
+
+			flush()
+			elements = append(elements, pathElement{index: index, isIndex: true})
+			i += end
+		default:
+			name.WriteByte(c)
+		}
+	}
+
+	flush()
+
+	return elements
+}
+
+// splitProjection splits a comma separated projection into its paths.
+func splitProjection(projection string) []AttributePath {
+	var paths []AttributePath
+	start := 0
+	for i := 0; i <= len(projection); i++ {
+		switch {
+		case i < len(projection) && projection[i] == '\\':
+			i++
+		case i == len(projection) || projection[i] == ',':
+			paths = append(paths, AttributePath(strings.TrimSpace(projection[start:i])))
+			start = i + 1
+		}
+	}
+
+	return paths
+}
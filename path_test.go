@@ -0,0 +1,51 @@
+package dynago
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAttributePath_elements(t *testing.T) {
+	tests := []struct {
+		name string
+		path AttributePath
+		want []pathElement
+	}{
+		{"name", "Id", []pathElement{{name: "Id"}}},
+		{"nested", "Address.City", []pathElement{{name: "Address"}, {name: "City"}}},
+		{"index", "Tags[0][12]", []pathElement{{name: "Tags"}, {index: 0, isIndex: true}, {index: 12, isIndex: true}}},
+		{"built", Path("Address", "Lines").Index(1).Field("Text"), []pathElement{
+			{name: "Address"}, {name: "Lines"}, {index: 1, isIndex: true}, {name: "Text"},
+		}},
+		{"escaped", Path("a.b", "c[0]", `d\`), []pathElement{{name: "a.b"}, {name: "c[0]"}, {name: `d\`}}},
+		{"not an index", "Tags[x]", []pathElement{{name: "Tags[x]"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.path.elements(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("elements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_splitProjection(t *testing.T) {
+	got := splitProjection(`Id, Address.City,` + string(Path("a,b")))
+	want := []AttributePath{"Id", "Address.City", `a\,b`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitProjection() = %v, want %v", got, want)
+	}
+}
+
+func Test_exprBuilder_path(t *testing.T) {
+	b := newExprBuilder()
+	got := b.path(Path("Address", "Address").Index(3).Field("a.b"))
+
+	if want := "#n0.#n0[3].#n1"; got != want {
+		t.Errorf("path() = %v, want %v", got, want)
+	}
+
+	if want := map[string]string{"#n0": "Address", "#n1": "a.b"}; !reflect.DeepEqual(b.names, want) {
+		t.Errorf("path() names = %v, want %v", b.names, want)
+	}
+}
@@ -1,21 +1,17 @@
 package dynago
 
-import "reflect"
+import (
+	"reflect"
+	"strings"
+)
 
 // buildProjection builds the projections used in DynamoDb
 // queries based off the given interface{}.
-func buildProjection(schema interface{}) (proj string) {
-	schemaType := reflect.TypeOf(schema)
-
-	schemaLength := schemaType.NumField()
-	for i := 0; i < schemaLength; i++ {
-		field := schemaType.Field(i)
-
-		proj += field.Name
-		if i < schemaLength-1 {
-			proj += ","
-		}
+func buildProjection(schema interface{}) string {
+	var names []string
+	for _, field := range schemaFields(reflect.TypeOf(schema)) {
+		names = append(names, string(Path(field.name)))
 	}
 
-	return
+	return strings.Join(names, ",")
 }
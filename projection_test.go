@@ -17,7 +17,18 @@ func Test_buildProjection(t *testing.T) {
 				Name string
 				Age  int
 			}{}},
-			"Name,Age,",
+			"Name,Age",
+		},
+		{
+			"tagged schema",
+			args{struct {
+				Name   string `dynago:"name"`
+				Age    int    `dynago:",range"`
+				Secret string `dynago:"-"`
+				Dotted string `dynago:"a.b"`
+				hidden string
+			}{}},
+			`name,Age,a\.b`,
 		},
 	}
 	for _, tt := range tests {
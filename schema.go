@@ -0,0 +1,239 @@
+package dynago
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"strings"
+)
+
+// schemaField describes how a single struct field is stored in DynamoDb.
+// The description is read from the field's dynago struct tag.
+//
+//  type Person struct {
+//    Country string `dynago:"country,hash"`
+//    Age     uint8  `dynago:",range"`
+//    Alias   string `dynago:"alias,omitempty"`
+//    Secret  string `dynago:"-"`
+//  }
+//
+// Slices and arrays tagged with set are stored as sets instead of
+// lists. Time fields tagged with unix or unixmilli are stored as seconds or
+// milliseconds since the Unix epoch instead of as strings.
+//
+// Without a name in the tag, the Go field name is used as the
+// attribute name. Fields tagged with "-" and unexported fields
+// are never stored.
+//
+// The fields of embedded structs, and of embedded pointers to structs,
+// are stored as if they were fields of the outer struct, unless the
+// embedded struct is given a name in its tag. Just like in Go, outer
+// fields win over embedded fields with the same name.
+type schemaField struct {
+	index        []int
+	name         string
+	keyType      types.KeyType
+	omitEmpty    bool
+	set          bool
+	timeEncoding timeEncoding
+}
+
+// schemaFields are the fields of the schema. They are resolved once
+// per type, so the returned fields must not be modified.
+func schemaFields(schemaType reflect.Type) []schemaField {
+	return structCodecFor(schemaType).fields
+}
+
+// resolveFields collects the fields of the schema, leaving out those
+// which are shadowed by outer fields with the same name.
+func resolveFields(schemaType reflect.Type) []schemaField {
+	all := collectFields(schemaType, nil, nil)
+
+	depths := make(map[string]int)
+	for _, field := range all {
+		if depth, ok := depths[field.name]; !ok || len(field.index) < depth {
+			depths[field.name] = len(field.index)
+		}
+	}
+
+	var fields []schemaField
+	for _, field := range all {
+		if depths[field.name] == len(field.index) {
+			fields = append(fields, field)
+			depths[field.name] = -1 // Only the first of equally deep fields
+		}
+	}
+
+	return fields
+}
+
+// collectFields collects the fields of the struct and of the structs
+// it embeds. The embedding structs are the structs already being
+// collected, whose embedded pointers are not followed again.
+func collectFields(schemaType reflect.Type, index []int, embedding []reflect.Type) (fields []schemaField) {
+	embedding = append(embedding[:len(embedding):len(embedding)], schemaType)
+	for i := 0; i < schemaType.NumField(); i++ {
+		structField := schemaType.Field(i)
+
+		tag := structField.Tag.Get("dynago")
+		if tag == "-" {
+			continue
+		}
+
+		options := strings.Split(tag, ",")
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		if embedded, ok := embeddedStruct(structField); ok && options[0] == "" {
+			if !containsType(embedding, embedded) {
+				fields = append(fields, collectFields(embedded, fieldIndex, embedding)...)
+			}
+
+			continue
+		}
+
+		if structField.PkgPath != "" {
+			continue // Unexported
+		}
+
+		field := schemaField{index: fieldIndex, name: structField.Name}
+		if options[0] != "" {
+			field.name = options[0]
+		}
+
+		for _, option := range options[1:] {
+			switch option {
+			case "hash":
+				field.keyType = types.KeyTypeHash
+			case "range":
+				field.keyType = types.KeyTypeRange
+			case "omitempty":
+				field.omitEmpty = true
+			case "set":
+				field.set = true
+			case "unix":
+				field.timeEncoding = timeUnix
+			case "unixmilli":
+				field.timeEncoding = timeUnixMilli
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	return
+}
+
+// embeddedStruct is the struct type of an embedded struct or embedded
+// pointer to a struct.
+func embeddedStruct(structField reflect.StructField) (reflect.Type, bool) {
+	if !structField.Anonymous {
+		return nil, false
+	}
+
+	switch fieldType := structField.Type; {
+	case fieldType.Kind() == reflect.Struct:
+		return fieldType, true
+	case fieldType.Kind() == reflect.Ptr && fieldType.Elem().Kind() == reflect.Struct:
+		return fieldType.Elem(), true
+	}
+
+	return nil, false
+}
+
+func containsType(types []reflect.Type, t reflect.Type) bool {
+	for _, other := range types {
+		if other == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldByIndex is the value of the field with the index, or false when
+// the field is promoted through a nil embedded pointer.
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(fieldIndex)
+	}
+
+	return value, true
+}
+
+// settableFieldByIndex is the value of the field with the index,
+// allocating the nil embedded pointers it is promoted through. Just like
+// for encoding/json, nil embedded pointers to unexported structs cannot
+// be allocated.
+func settableFieldByIndex(value reflect.Value, index []int) (reflect.Value, error) {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !value.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot allocate embedded pointer to unexported %v", value.Type().Elem())
+				}
+
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(fieldIndex)
+	}
+
+	return value, nil
+}
+
+// keyFields finds the hash and range key fields of the schema.
+// The range key is nil when the schema only has a hash key.
+//
+// Schemas without any key tags fall back to using the first field as
+// the hash key and the second field as the range key.
+func keyFields(fields []schemaField) (hash *schemaField, rangeKey *schemaField) {
+	tagged := false
+	for i := range fields {
+		switch fields[i].keyType {
+		case types.KeyTypeHash:
+			tagged = true
+			if hash == nil {
+				hash = &fields[i]
+			}
+		case types.KeyTypeRange:
+			tagged = true
+			if rangeKey == nil {
+				rangeKey = &fields[i]
+			}
+		}
+	}
+
+	if tagged {
+		if hash == nil {
+			for i := range fields {
+				if &fields[i] != rangeKey {
+					hash = &fields[i]
+					break
+				}
+			}
+		}
+
+		return
+	}
+
+	if len(fields) > 0 {
+		hash = &fields[0]
+	}
+
+	if len(fields) > 1 {
+		rangeKey = &fields[1]
+	}
+
+	return
+}
@@ -0,0 +1,77 @@
+package dynago
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_keyFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		schema    interface{}
+		wantHash  string
+		wantRange string
+	}{
+		{
+			"untagged",
+			struct {
+				Id   int
+				Name string
+				Age  int
+			}{},
+			"Id",
+			"Name",
+		},
+		{
+			"single field",
+			struct{ Id int }{},
+			"Id",
+			"",
+		},
+		{
+			"tagged",
+			struct {
+				Age  int    `dynago:"age,range"`
+				Name string `dynago:"name"`
+				Id   int    `dynago:"id,hash"`
+			}{},
+			"id",
+			"age",
+		},
+		{
+			"hash only",
+			struct {
+				Name string
+				Id   int `dynago:",hash"`
+			}{},
+			"Id",
+			"",
+		},
+		{
+			"range only",
+			struct {
+				Name string `dynago:",range"`
+				Id   int
+			}{},
+			"Id",
+			"Name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, rangeKey := keyFields(schemaFields(reflect.TypeOf(tt.schema)))
+			if hash.name != tt.wantHash {
+				t.Errorf("keyFields() hash = %v, want %v", hash.name, tt.wantHash)
+			}
+
+			var gotRange string
+			if rangeKey != nil {
+				gotRange = rangeKey.name
+			}
+
+			if gotRange != tt.wantRange {
+				t.Errorf("keyFields() range = %v, want %v", gotRange, tt.wantRange)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package dynago
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"sort"
+)
+
+// StringSet is stored as a DynamoDb string set rather than a list.
+//
+// Sets are unordered and hold every element once, so duplicates are
+// dropped when the set is stored. Empty sets are stored as NULL, since
+// DynamoDb has no empty sets.
+type StringSet []string
+
+// NumberSet is stored as a DynamoDb number set rather than a list.
+// See StringSet.
+type NumberSet []Number
+
+// BinarySet is stored as a DynamoDb binary set rather than a list.
+// See StringSet.
+type BinarySet [][]byte
+
+// MarshalAttribute implements AttributeMarshaler.
+func (s StringSet) MarshalAttribute() (types.AttributeValue, error) {
+	return encodeSet(reflect.ValueOf(s))
+}
+
+// UnmarshalAttribute implements AttributeUnmarshaler.
+func (s *StringSet) UnmarshalAttribute(attribute types.AttributeValue) error {
+	return decodeList(attribute, reflect.ValueOf((*[]string)(s)).Elem())
+}
+
+// MarshalAttribute implements AttributeMarshaler.
+func (s NumberSet) MarshalAttribute() (types.AttributeValue, error) {
+	return encodeSet(reflect.ValueOf(s))
+}
+
+// UnmarshalAttribute implements AttributeUnmarshaler.
+func (s *NumberSet) UnmarshalAttribute(attribute types.AttributeValue) error {
+	return decodeList(attribute, reflect.ValueOf((*[]Number)(s)).Elem())
+}
+
+// MarshalAttribute implements AttributeMarshaler.
+func (s BinarySet) MarshalAttribute() (types.AttributeValue, error) {
+	return encodeSet(reflect.ValueOf(s))
+}
+
+// UnmarshalAttribute implements AttributeUnmarshaler.
+func (s *BinarySet) UnmarshalAttribute(attribute types.AttributeValue) error {
+	return decodeList(attribute, reflect.ValueOf((*[][]byte)(s)).Elem())
+}
+
+// encodeSet encodes the elements of a slice or array, or the keys of
+// a map[T]struct{}, as a set. The type of set follows from the type of
+// the elements, which must be strings, numbers or binaries.
+func encodeSet(value reflect.Value) (types.AttributeValue, error) {
+	var elems []reflect.Value
+	var elemType reflect.Type
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		elemType = value.Type().Elem()
+		for i := 0; i < value.Len(); i++ {
+			elems = append(elems, value.Index(i))
+		}
+	case reflect.Map:
+		elemType = value.Type().Key()
+		elems = value.MapKeys()
+	default:
+		return nil, fmt.Errorf("cannot encode %v as a set", value.Type())
+	}
+
+	kind := setType(elemType)
+	if kind == "" {
+		return nil, fmt.Errorf("cannot encode %v as a set", value.Type())
+	}
+
+	seen := make(map[string]bool)
+	var members []string
+	for i, elem := range elems {
+		attributeValue, err := encodeValue(elem)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		var s string
+		switch a := attributeValue.(type) {
+		case *types.AttributeValueMemberS:
+			s = a.Value
+		case *types.AttributeValueMemberN:
+			s = a.Value
+		case *types.AttributeValueMemberB:
+			s = string(a.Value)
+		default:
+			return nil, fmt.Errorf("[%d]: cannot store %T in a set", i, attributeValue)
+		}
+
+		if !seen[s] {
+			seen[s] = true
+			members = append(members, s)
+		}
+	}
+
+	if len(members) == 0 {
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	}
+
+	if value.Kind() == reflect.Map {
+		sort.Strings(members) // Keeps maps, which have no order, stable
+	}
+
+	switch kind {
+	case "SS":
+		return &types.AttributeValueMemberSS{Value: members}, nil
+	case "NS":
+		return &types.AttributeValueMemberNS{Value: members}, nil
+	default:
+		binaries := make([][]byte, len(members))
+		for i, s := range members {
+			binaries[i] = []byte(s)
+		}
+
+		return &types.AttributeValueMemberBS{Value: binaries}, nil
+	}
+}
+
+// setType is the type of set which holds elements of the given type,
+// or an empty string when there is no such set.
+func setType(elemType reflect.Type) string {
+	switch elemType {
+	case numberType:
+		return "NS"
+	case timeType:
+		return "SS"
+	}
+
+	switch elemType.Kind() {
+	case reflect.String:
+		return "SS"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "NS"
+	case reflect.Slice, reflect.Array:
+		if elemType.Elem().Kind() == reflect.Uint8 {
+			return "BS"
+		}
+	}
+
+	return ""
+}
+
+// isSetMap reports whether the map is a set of its keys, which is the
+// case for map[T]struct{}.
+func isSetMap(mapType reflect.Type) bool {
+	elemType := mapType.Elem()
+
+	return elemType.Kind() == reflect.Struct && elemType.NumField() == 0
+}
+
+// decodeSet decodes the elements of a set into the keys of a
+// map[T]struct{}.
+func decodeSet(attribute types.AttributeValue, value reflect.Value) error {
+	var elems []types.AttributeValue
+	switch a := attribute.(type) {
+	case *types.AttributeValueMemberSS:
+		for _, s := range a.Value {
+			elems = append(elems, &types.AttributeValueMemberS{Value: s})
+		}
+	case *types.AttributeValueMemberNS:
+		for _, n := range a.Value {
+			elems = append(elems, &types.AttributeValueMemberN{Value: n})
+		}
+	case *types.AttributeValueMemberBS:
+		for _, b := range a.Value {
+			elems = append(elems, &types.AttributeValueMemberB{Value: b})
+		}
+	default:
+		return decodeMismatch(attribute, value)
+	}
+
+	set := reflect.MakeMapWithSize(value.Type(), len(elems))
+	present := reflect.New(value.Type().Elem()).Elem()
+	for i, elem := range elems {
+		key := reflect.New(value.Type().Key()).Elem()
+		if err := decodeValue(elem, key); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		set.SetMapIndex(key, present)
+	}
+
+	value.Set(set)
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package dynago
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"testing"
+)
+
+func Test_sets(t *testing.T) {
+	type sets struct {
+		Id     string
+		Tags   []string
+		Colors []string `dynago:",set"`
+		Labels StringSet
+		Sizes  map[int]struct{}
+		Keys   BinarySet
+		Hashes map[[2]byte]struct{}
+		Empty  StringSet
+	}
+
+	item, err := buildItem(sets{
+		Id:     "abc",
+		Tags:   []string{"a", "a"},
+		Colors: []string{"red", "blue", "red"},
+		Labels: StringSet{"x"},
+		Sizes:  map[int]struct{}{42: {}, 7: {}},
+		Keys:   BinarySet{{1}, {2}},
+		Hashes: map[[2]byte]struct{}{{3, 4}: {}, {1, 2}: {}},
+		Empty:  StringSet{},
+	})
+	if err != nil {
+		t.Fatalf("buildItem() error = %v", err)
+	}
+
+	wantItem := map[string]types.AttributeValue{
+		"Id": &types.AttributeValueMemberS{Value: "abc"},
+		"Tags": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+			&types.AttributeValueMemberS{Value: "a"},
+			&types.AttributeValueMemberS{Value: "a"},
+		}},
+		"Colors": &types.AttributeValueMemberSS{Value: []string{"red", "blue"}},
+		"Labels": &types.AttributeValueMemberSS{Value: []string{"x"}},
+		"Sizes":  &types.AttributeValueMemberNS{Value: []string{"42", "7"}},
+		"Keys":   &types.AttributeValueMemberBS{Value: [][]byte{{1}, {2}}},
+		"Hashes": &types.AttributeValueMemberBS{Value: [][]byte{{1, 2}, {3, 4}}},
+		"Empty":  &types.AttributeValueMemberNULL{Value: true},
+	}
+	if !reflect.DeepEqual(item, wantItem) {
+		t.Errorf("buildItem() = %v, want %v", item, wantItem)
+	}
+
+	got, err := constructItem(item, sets{})
+	if err != nil {
+		t.Fatalf("constructItem() error = %v", err)
+	}
+
+	want := sets{
+		Id:     "abc",
+		Tags:   []string{"a", "a"},
+		Colors: []string{"red", "blue"},
+		Labels: StringSet{"x"},
+		Sizes:  map[int]struct{}{42: {}, 7: {}},
+		Keys:   BinarySet{{1}, {2}},
+		Hashes: map[[2]byte]struct{}{{1, 2}: {}, {3, 4}: {}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("constructItem() = %v, want %v", got, want)
+	}
+}
+
+func Test_encodeSet_invalid(t *testing.T) {
+	type invalid struct {
+		Id    string
+		Flags []bool `dynago:",set"`
+	}
+
+	if _, err := buildItem(invalid{Id: "abc", Flags: []bool{true}}); err == nil {
+		t.Errorf("buildItem() error = nil, want no set of bools")
+	}
+}
@@ -1,8 +1,11 @@
 package dynago
 
 import (
+	"context"
+	"errors"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
 )
 
 // Table provides all item operations for your DynamoDb Table.
@@ -11,30 +14,252 @@ import (
 // The operations performed on this Table will result in an
 // interface whose type is the same as the Schema field.
 // This is true unless an error is returned instead.
+//
+// HashKey and RangeKey are the names of the Table's key attributes.
+// RangeKey is empty for tables without a range key.
+//
+// BatchConcurrency is how many batches PutAll and Delete write at
+// once. By default batches are written one after the other.
+//
+// A Table makes its requests with the Client which created it, while
+// Tables which are constructed directly use the default Client.
 type Table struct {
-	Name       string
-	Schema     interface{}
-	Projection string
+	Name             string
+	Schema           interface{}
+	Projection       string
+	HashKey          string
+	RangeKey         string
+	BatchConcurrency int
+
+	client *Client
 }
 
-// NewTable creates a new Table.
+// NewTable creates a new Table with the default Client.
 // A Table cannot be created if the Table does not exist in DynamoDb.
 //
 // The operations performed on this Table will result in an
 // interface whose type is the same as the schema parameter.
 // This is true unless an error is returned instead.
 func NewTable(name string, schema interface{}) (*Table, error) {
-	output, err := dbClient.DescribeTable(dbCtx, &dynamodb.DescribeTableInput{TableName: &name})
+	return defaultClient.NewTable(name, schema)
+}
+
+// NewTableCtx is the same as NewTable, describing the table with the
+// context.
+func NewTableCtx(ctx context.Context, name string, schema interface{}) (*Table, error) {
+	return defaultClient.NewTableCtx(ctx, name, schema)
+}
+
+// NewTable creates a new Table which makes its requests with the
+// Client. See the package-level NewTable.
+func (c *Client) NewTable(name string, schema interface{}) (*Table, error) {
+	return c.NewTableCtx(dbCtx, name, schema)
+}
+
+// NewTableCtx is the same as Client.NewTable, describing the table with
+// the context.
+func (c *Client) NewTableCtx(ctx context.Context, name string, schema interface{}) (*Table, error) {
+	output, err := c.db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: &name})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.newTable(*output.Table.TableName, schema, output.Table.KeySchema), nil
+}
+
+func (c *Client) newTable(name string, schema interface{}, keySchema []types.KeySchemaElement) *Table {
+	table := &Table{Name: name, Schema: schema, Projection: buildProjection(schema), client: c}
+	for _, element := range keySchema {
+		switch element.KeyType {
+		case types.KeyTypeHash:
+			table.HashKey = *element.AttributeName
+		case types.KeyTypeRange:
+			table.RangeKey = *element.AttributeName
+		}
+	}
+
+	return table
+}
+
+// keyNames are the names of the key attributes of the Table.
+// When the key schema is unknown, the keys of the schema are used.
+func (t Table) keyNames() (hashKey, rangeKey string) {
+	if t.HashKey != "" {
+		return t.HashKey, t.RangeKey
+	}
+
+	schemaType := reflect.TypeOf(t.Schema)
+	if schemaType != nil && schemaType.Kind() == reflect.Ptr {
+		schemaType = schemaType.Elem()
+	}
+
+	if schemaType == nil || schemaType.Kind() != reflect.Struct {
+		return
+	}
+
+	hash, rangeField := keyFields(schemaFields(schemaType))
+	if hash != nil {
+		hashKey = hash.name
+	}
+
+	if rangeField != nil {
+		rangeKey = rangeField.name
+	}
+
+	return
+}
+
+// key builds the primary key of an item for this Table.
+// The item is either an instance of the schema, where only the key
+// fields need to be set, or a map of key attribute names to values.
+func (t Table) key(item interface{}) (map[string]types.AttributeValue, error) {
+	var values map[string]types.AttributeValue
+	var err error
+	if attributes, ok := item.(map[string]interface{}); ok {
+		values, err = fromMap(attributes)
+	} else {
+		values, err = buildItem(item)
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	return &Table{*output.Table.TableName, schema, buildProjection(schema)}, nil
+	hashKey, rangeKey := t.keyNames()
+	if hashKey == "" {
+		// Neither the key schema nor the Schema is known, so trust the item
+		hashKey, rangeKey = Table{Schema: item}.keyNames()
+	}
+
+	key := make(map[string]types.AttributeValue)
+	for _, name := range []string{hashKey, rangeKey} {
+		if value, ok := values[name]; ok {
+			key[name] = value
+		}
+	}
+
+	return key, nil
+}
+
+// Get fetches a single item from your Table by its primary key.
+// The key is an instance of your Table's schema where only the key
+// fields need to be set.
+//
+// If no item exists for the key an *ItemNotFoundError is returned.
+//
+//  result, err := table.Get(MySchema{Id: 123})
+//  data := result.(MySchema)
+func (t Table) Get(key interface{}) (interface{}, error) { return t.GetCtx(dbCtx, key) }
+
+// GetCtx is the same as Table.Get, making the request with the context.
+func (t Table) GetCtx(ctx context.Context, key interface{}) (interface{}, error) {
+	return t.get(ctx, key, false)
+}
+
+// GetConsistent behaves the same as Table.Get but performs a strongly
+// consistent read, so the item reflects every write that succeeded
+// before the read.
+func (t Table) GetConsistent(key interface{}) (interface{}, error) {
+	return t.GetConsistentCtx(dbCtx, key)
+}
+
+// GetConsistentCtx is the same as Table.GetConsistent, making the request
+// with the context.
+func (t Table) GetConsistentCtx(ctx context.Context, key interface{}) (interface{}, error) {
+	return t.get(ctx, key, true)
+}
+
+func (t Table) get(ctx context.Context, key interface{}, consistent bool) (interface{}, error) {
+	itemKey, err := t.key(key)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newExprBuilder()
+	projection := b.projection(t.Projection)
+
+	output, err := t.db().GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:                &t.Name,
+		Key:                      itemKey,
+		ConsistentRead:           &consistent,
+		ProjectionExpression:     projection,
+		ExpressionAttributeNames: b.attributeNames(),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Item == nil {
+		return nil, &ItemNotFoundError{t.Name, key}
+	}
+
+	return constructItem(output.Item, t.Schema)
+}
+
+// GetMany fetches the items for many keys at once, using as few
+// requests as possible. The keys are the same as for Table.Get.
+//
+// The returned items are in the same order as the keys. When some keys
+// have no item, their items are nil and a *MissingItemsError is
+// returned along with the items which were found.
+//
+//  results, err := table.GetMany([]interface{}{MySchema{Id: 1}, MySchema{Id: 2}})
+func (t Table) GetMany(keys []interface{}) ([]interface{}, error) {
+	return t.GetManyCtx(dbCtx, keys)
+}
+
+// GetManyCtx is the same as Table.GetMany. Once the context is done, no
+// more batches are requested.
+func (t Table) GetManyCtx(ctx context.Context, keys []interface{}) ([]interface{}, error) {
+	var requestKeys []map[string]types.AttributeValue
+	for _, key := range keys {
+		requestKey, err := t.key(key)
+		if err != nil {
+			return nil, err
+		}
+
+		requestKeys = append(requestKeys, requestKey)
+	}
+
+	items, err := t.batchGet(ctx, requestKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(keys))
+	var missing *MissingItemsError
+	for i, item := range items {
+		if item == nil {
+			if missing == nil {
+				missing = &MissingItemsError{TableName: t.Name}
+			}
+
+			missing.Indexes = append(missing.Indexes, i)
+			missing.Keys = append(missing.Keys, keys[i])
+			continue
+		}
+
+		if results[i], err = constructItem(item, t.Schema); err != nil {
+			return nil, err
+		}
+	}
+
+	if missing != nil {
+		return results, missing
+	}
+
+	return results, nil
 }
 
 // Query allows query operation access on the Table.
 // A Condition is given as an argument for easy usage.
 //
+// The Condition is split using the Table's key schema. An Eq on the hash
+// key and one predicate on the range key make up the key condition,
+// while everything else filters the queried items. This requires the
+// Condition to be joined by And, otherwise use QueryWithFilter.
+//
 // The result of Query, in the case of no error being returned,
 // will be the same type as your Table's schema.
 //
@@ -43,8 +268,71 @@ func NewTable(name string, schema interface{}) (*Table, error) {
 //  data := result.(MySchema)
 //
 func (t Table) Query(condition Condition) ([]interface{}, error) {
-	expr, values := condition.buildExpr()
-	return t.QueryWithExpr(*expr, values, condition.options.limit)
+	return t.QueryCtx(dbCtx, condition)
+}
+
+// QueryCtx is the same as Table.Query. Once the context is done, no more
+// pages are requested and the context's error is returned.
+func (t Table) QueryCtx(ctx context.Context, condition Condition) ([]interface{}, error) {
+	return t.QueryIterCtx(ctx, condition).all()
+}
+
+// QueryIter is the same as Table.Query, but the items are read with an
+// Iter, which requests the pages one at a time as the items are read.
+//
+//  iter := table.QueryIter(dynago.Eq("Id", dynago.N(123)))
+//  defer iter.Close()
+//
+//  for iter.Next() {
+//    data := iter.Item().(MySchema)
+//  }
+func (t Table) QueryIter(condition Condition) *Iter { return t.QueryIterCtx(dbCtx, condition) }
+
+// QueryIterCtx is the same as Table.QueryIter, making the requests with
+// the context.
+func (t Table) QueryIterCtx(ctx context.Context, condition Condition) *Iter {
+	hashKey, rangeKey := t.keyNames()
+	key, filter, err := condition.splitKey(hashKey, rangeKey)
+	if err != nil {
+		return errIter(err)
+	}
+
+	return t.QueryWithFilterIterCtx(ctx, key, filter)
+}
+
+// QueryWithFilter behaves the same as Table.Query but the key condition
+// and the filter are given separately. Use All() when no filter is needed.
+//
+//  result, err := table.QueryWithFilter(
+//    dynago.Eq("Id", dynago.N(123)),
+//    dynago.Eq("Color", dynago.S("Brown")).Or(dynago.Eq("Color", dynago.S("White"))),
+//  )
+func (t Table) QueryWithFilter(key Condition, filter Condition) ([]interface{}, error) {
+	return t.QueryWithFilterCtx(dbCtx, key, filter)
+}
+
+// QueryWithFilterCtx is the same as Table.QueryWithFilter, with the
+// context ending the pagination the same way as for QueryCtx.
+func (t Table) QueryWithFilterCtx(ctx context.Context, key Condition, filter Condition) ([]interface{}, error) {
+	return t.QueryWithFilterIterCtx(ctx, key, filter).all()
+}
+
+// QueryWithFilterIter is the same as Table.QueryWithFilter, but the
+// items are read with an Iter like for Table.QueryIter.
+func (t Table) QueryWithFilterIter(key Condition, filter Condition) *Iter {
+	return t.QueryWithFilterIterCtx(dbCtx, key, filter)
+}
+
+// QueryWithFilterIterCtx is the same as Table.QueryWithFilterIter,
+// making the requests with the context.
+func (t Table) QueryWithFilterIterCtx(ctx context.Context, key Condition, filter Condition) *Iter {
+	b := newExprBuilder()
+	expr := key.buildExpr(b)
+	if expr == nil {
+		return errIter(errors.New("query requires a key condition"))
+	}
+
+	return t.query(ctx, *expr, filter.buildExpr(b), b, key.options.limit)
 }
 
 // QueryWithExpr allows for lower level usage of your Table.
@@ -57,86 +345,114 @@ func (t Table) Query(condition Condition) ([]interface{}, error) {
 //
 //  result, _ := table.QueryWithExpr("Id = :Id", map[string]interface{}{":Id": "123"}, nil)
 func (t Table) QueryWithExpr(expr string, values map[string]interface{}, limit *int32) ([]interface{}, error) {
-	var items []map[string]types.AttributeValue
+	return t.QueryWithExprCtx(dbCtx, expr, values, limit)
+}
+
+// QueryWithExprCtx is the same as Table.QueryWithExpr, with the context
+// ending the pagination the same way as for QueryCtx.
+func (t Table) QueryWithExprCtx(ctx context.Context, expr string, values map[string]interface{}, limit *int32) ([]interface{}, error) {
+	b := newExprBuilder()
+	for k, v := range values {
+		b.values[k] = v
+	}
 
-	var doQuery func(lastKey map[string]types.AttributeValue) error
-	doQuery = func(lastKey map[string]types.AttributeValue) error {
-		output, err := dbClient.Query(dbCtx, &dynamodb.QueryInput{
+	return t.query(ctx, expr, nil, b, limit).all()
+}
+
+func (t Table) query(ctx context.Context, expr string, filter *string, b *exprBuilder, limit *int32) *Iter {
+	projection := b.projection(t.Projection)
+	values, err := b.attributeValues()
+	if err != nil {
+		return errIter(err)
+	}
+
+	return newIter(ctx, t.Schema, limit, func(
+		ctx context.Context,
+		lastKey map[string]types.AttributeValue,
+		limit *int32,
+	) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+		output, err := t.db().Query(ctx, &dynamodb.QueryInput{
 			TableName:                 &t.Name,
-			ExpressionAttributeValues: fromMap(values),
+			ExpressionAttributeNames:  b.attributeNames(),
+			ExpressionAttributeValues: values,
 			KeyConditionExpression:    &expr,
+			FilterExpression:          filter,
 			Limit:                     limit,
 			ExclusiveStartKey:         lastKey,
-			ProjectionExpression:      &t.Projection,
+			ProjectionExpression:      projection,
 		})
 
 		if err != nil {
-			return err
-		}
-
-		items = append(items, output.Items...)
-
-		// No LastEvaluatedKey or the given limit is met
-		if len(output.LastEvaluatedKey) > 0 && limit == nil || limit != nil && int32(len(items)) < *limit {
-			return doQuery(output.LastEvaluatedKey)
+			return nil, nil, err
 		}
 
-		return nil
-	}
-
-	if err := doQuery(nil); err != nil {
-		return nil, err
-	}
-
-	return constructItems(items, t.Schema)
+		return output.Items, output.LastEvaluatedKey, nil
+	})
 }
 
 // ScanAll simply scans all items in your Table and returns them.
 //
 // Scan operations normally are not fast unless your data set is small.
 // Do not use this on larger tables unless you know what you're doing.
-func (t Table) ScanAll() ([]interface{}, error) { return t.Scan(All()) }
+func (t Table) ScanAll() ([]interface{}, error) { return t.ScanAllCtx(dbCtx) }
+
+// ScanAllCtx is the same as Table.ScanAll, with the context ending the
+// pagination the same way as for ScanCtx.
+func (t Table) ScanAllCtx(ctx context.Context) ([]interface{}, error) {
+	return t.ScanCtx(ctx, All())
+}
 
 // Scan performs a basic item scan on your Table using the provided Condition.
 //
 // Scan operations normally are not fast unless your data set is small.
 // Do not use this on larger tables unless you know what you're doing.
 func (t Table) Scan(condition Condition) ([]interface{}, error) {
-	expr, values := condition.buildExpr()
-	limit := condition.options.limit
+	return t.ScanCtx(dbCtx, condition)
+}
+
+// ScanCtx is the same as Table.Scan. Once the context is done, no more
+// pages are requested and the context's error is returned.
+func (t Table) ScanCtx(ctx context.Context, condition Condition) ([]interface{}, error) {
+	return t.ScanIterCtx(ctx, condition).all()
+}
 
-	var items []map[string]types.AttributeValue
+// ScanIter is the same as Table.Scan, but the items are read with an
+// Iter, which requests the pages one at a time as the items are read.
+func (t Table) ScanIter(condition Condition) *Iter { return t.ScanIterCtx(dbCtx, condition) }
 
-	var doScan func(lastKey map[string]types.AttributeValue) error
-	doScan = func(lastKey map[string]types.AttributeValue) error {
-		output, err := dbClient.Scan(dbCtx, &dynamodb.ScanInput{
+// ScanIterCtx is the same as Table.ScanIter, making the requests with
+// the context.
+func (t Table) ScanIterCtx(ctx context.Context, condition Condition) *Iter {
+	b := newExprBuilder()
+	expr := condition.buildExpr(b)
+	projection := b.projection(t.Projection)
+
+	values, err := b.attributeValues()
+	if err != nil {
+		return errIter(err)
+	}
+
+	return newIter(ctx, t.Schema, condition.options.limit, func(
+		ctx context.Context,
+		lastKey map[string]types.AttributeValue,
+		limit *int32,
+	) ([]map[string]types.AttributeValue, map[string]types.AttributeValue, error) {
+		output, err := t.db().Scan(ctx, &dynamodb.ScanInput{
 			TableName:                 &t.Name,
-			ExpressionAttributeValues: fromMap(values),
+			ExpressionAttributeNames:  b.attributeNames(),
+			ExpressionAttributeValues: values,
 			FilterExpression:          expr,
 			Limit:                     limit,
 			ExclusiveStartKey:         lastKey,
-			ProjectionExpression:      &t.Projection,
+			ProjectionExpression:      projection,
 		})
 
 		if err != nil {
-			return err
-		}
-
-		items = append(items, output.Items...)
-
-		// No LastEvaluatedKey or the given limit is met
-		if len(output.LastEvaluatedKey) > 0 && limit == nil || limit != nil && int32(len(items)) < *limit {
-			return doScan(output.LastEvaluatedKey)
+			return nil, nil, err
 		}
 
-		return nil
-	}
-
-	if err := doScan(nil); err != nil {
-		return nil, err
-	}
-
-	return constructItems(items, t.Schema)
+		return output.Items, output.LastEvaluatedKey, nil
+	})
 }
 
 // Put allows you to put an item into your Table.
@@ -146,18 +462,71 @@ func (t Table) Scan(condition Condition) ([]interface{}, error) {
 // The returned value will be the put item, unless an error occurred.
 //
 //  put, err := dynago.Put(item)
-func (t Table) Put(item interface{}) (interface{}, error) { return t.PutWithCondition(All(), item) }
+func (t Table) Put(item interface{}) (interface{}, error) { return t.PutCtx(dbCtx, item) }
+
+// PutCtx is the same as Table.Put, making the request with the context.
+func (t Table) PutCtx(ctx context.Context, item interface{}) (interface{}, error) {
+	return t.PutWithConditionCtx(ctx, All(), item)
+}
+
+// PutAll puts many items into your Table using as few requests as
+// possible. There are no conditions, but unlike Put, the items are
+// not guaranteed to be put in order.
+//
+// The returned values are the put items. If some of the items could not
+// be put, a *BatchWriteError reports each of them.
+//
+//  put, err := table.PutAll(items)
+//  var batchErr *dynago.BatchWriteError
+//  if errors.As(err, &batchErr) {
+//    for _, failure := range batchErr.Failures {
+//      log.Printf("could not put %v: %v", failure.Item, failure.Err)
+//    }
+//  }
+func (t Table) PutAll(items []interface{}) ([]interface{}, error) {
+	return t.PutAllCtx(dbCtx, items)
+}
+
+// PutAllCtx is the same as Table.PutAll. Once the context is done, no
+// more batches are written and the unwritten items fail with the
+// context's error.
+func (t Table) PutAllCtx(ctx context.Context, items []interface{}) ([]interface{}, error) {
+	errs := t.writeAll(ctx, items, func(item interface{}) (types.WriteRequest, error) {
+		toPut, err := buildItem(item)
+
+		return types.WriteRequest{PutRequest: &types.PutRequest{Item: toPut}}, err
+	})
+
+	return batchResult(items, errs)
+}
 
 // PutWithCondition behaves the same as Table.Put but it  accepts a
 // Condition that must be met before putting the given item.
 func (t Table) PutWithCondition(condition Condition, item interface{}) (interface{}, error) {
-	toPut := buildItem(item)
-	expr, values := condition.buildExpr()
+	return t.PutWithConditionCtx(dbCtx, condition, item)
+}
 
-	_, err := dbClient.PutItem(dbCtx, &dynamodb.PutItemInput{
+// PutWithConditionCtx is the same as Table.PutWithCondition, making the
+// request with the context.
+func (t Table) PutWithConditionCtx(ctx context.Context, condition Condition, item interface{}) (interface{}, error) {
+	toPut, err := buildItem(item)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newExprBuilder()
+	expr := condition.buildExpr(b)
+
+	values, err := b.attributeValues()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = t.db().PutItem(ctx, &dynamodb.PutItemInput{
 		TableName:                 &t.Name,
 		Item:                      toPut,
-		ExpressionAttributeValues: fromMap(values),
+		ExpressionAttributeNames:  b.attributeNames(),
+		ExpressionAttributeValues: values,
 		ConditionExpression:       expr,
 	})
 
@@ -168,17 +537,89 @@ func (t Table) PutWithCondition(condition Condition, item interface{}) (interfac
 	return item, nil
 }
 
+// Update applies the Update to the item with the given key, as long as
+// the Condition is met. Use All() when no Condition is needed.
+//
+// The returned value is the item chosen by Update.WithReturnValues,
+// which is the updated item unless told otherwise.
+//
+//  updated, err := table.Update(
+//    MySchema{Id: 123},
+//    dynago.Set("Visits", dynago.Plus(dynago.Field("Visits"), dynago.N(1))),
+//    dynago.All(),
+//  )
+func (t Table) Update(key interface{}, update Update, condition Condition) (interface{}, error) {
+	return t.UpdateCtx(dbCtx, key, update, condition)
+}
+
+// UpdateCtx is the same as Table.Update, making the request with the
+// context.
+func (t Table) UpdateCtx(ctx context.Context, key interface{}, update Update, condition Condition) (interface{}, error) {
+	itemKey, err := t.key(key)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newExprBuilder()
+	updateExpr, err := update.buildExpr(b)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := condition.buildExpr(b)
+
+	values, err := b.attributeValues()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := t.db().UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 &t.Name,
+		Key:                       itemKey,
+		UpdateExpression:          &updateExpr,
+		ConditionExpression:       expr,
+		ExpressionAttributeNames:  b.attributeNames(),
+		ExpressionAttributeValues: values,
+		ReturnValues:              update.returnValues.toReturnValue(),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if output.Attributes == nil {
+		return nil, nil
+	}
+
+	return constructItem(output.Attributes, t.Schema)
+}
+
 // DeleteItem attempts to delete the item from your Table.
 // Unless an error occurs, the returned value will be the item
 // that was deleted.
 //
+// Only the key of the item is used for the deletion, so the item can be
+// a whole item or just its key. A key is either an instance of your
+// schema with the key fields set or a map of key attribute names to values.
+//
+//  deleted, err := table.DeleteItem(map[string]interface{}{"Id": 123})
+//
 // For a more powerful deletion checkout Delete.
 func (t Table) DeleteItem(item interface{}) (interface{}, error) {
-	toDelete := buildItem(item)
+	return t.DeleteItemCtx(dbCtx, item)
+}
+
+// DeleteItemCtx is the same as Table.DeleteItem, making the request with
+// the context.
+func (t Table) DeleteItemCtx(ctx context.Context, item interface{}) (interface{}, error) {
+	key, err := t.key(item)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := dbClient.DeleteItem(dbCtx, &dynamodb.DeleteItemInput{
+	_, err = t.db().DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: &t.Name,
-		Key:       toDelete,
+		Key:       key,
 	})
 
 	if err != nil {
@@ -199,28 +640,26 @@ func (t Table) DeleteItem(item interface{}) (interface{}, error) {
 //    log.Fatalln("We just deleted all children!")
 //	}
 //
-// Under the hood, a query is run with the given Condition and a bulk
-// deletion is attempted.
+// Under the hood, a query is run with the given Condition and the
+// matched items are deleted in batches. If some deletions fail, the
+// items which were deleted are returned along with a *BatchWriteError.
 func (t Table) Delete(condition Condition) (interface{}, error) {
-	items, err := t.Query(condition)
+	return t.DeleteCtx(dbCtx, condition)
+}
+
+// DeleteCtx is the same as Table.Delete, where the context covers both
+// the query and the deletions.
+func (t Table) DeleteCtx(ctx context.Context, condition Condition) (interface{}, error) {
+	items, err := t.QueryCtx(ctx, condition)
 	if err != nil {
 		return nil, err
 	}
 
-	var writes []types.WriteRequest
-	for _, item := range items {
-		writes = append(writes, types.WriteRequest{DeleteRequest: &types.DeleteRequest{
-			Key: buildItem(item),
-		}})
-	}
+	errs := t.writeAll(ctx, items, func(item interface{}) (types.WriteRequest, error) {
+		key, err := t.key(item)
 
-	_, err = dbClient.BatchWriteItem(dbCtx, &dynamodb.BatchWriteItemInput{
-		RequestItems: map[string][]types.WriteRequest{t.Name: writes},
+		return types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}}, err
 	})
 
-	if err != nil {
-		return nil, err
-	}
-
-	return items, nil
+	return batchResult(items, errs)
 }
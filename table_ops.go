@@ -1,6 +1,7 @@
 package dynago
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -20,53 +21,83 @@ import (
 //
 //  dynago.CreateTable("TestTable", Person{})
 //
+// The first field becomes the hash key and the second field becomes
+// the range key, unless the keys are declared with dynago struct tags.
+// Only the key fields are defined when creating the table.
+//
+//  type Person struct {
+//    Age int    `dynago:"age,range"`
+//    Id  string `dynago:"id,hash"`
+//    Bio string `dynago:"bio,omitempty"`
+//  }
+//
 // The created table exposes various DynamoDb API calls such as
-// Table.Query and Table.Put.
+// Table.Query and Table.Put, which are made with the default Client.
 func CreateTable(name string, schema interface{}) (*Table, error) {
+	return defaultClient.CreateTable(name, schema)
+}
+
+// CreateTableCtx is the same as CreateTable, creating the table with
+// the context.
+func CreateTableCtx(ctx context.Context, name string, schema interface{}) (*Table, error) {
+	return defaultClient.CreateTableCtx(ctx, name, schema)
+}
+
+// CreateTable creates a DynamoDb table which makes its requests with
+// the Client. See the package-level CreateTable.
+func (c *Client) CreateTable(name string, schema interface{}) (*Table, error) {
+	return c.CreateTableCtx(dbCtx, name, schema)
+}
+
+// CreateTableCtx is the same as Client.CreateTable, creating the table
+// with the context.
+func (c *Client) CreateTableCtx(ctx context.Context, name string, schema interface{}) (*Table, error) {
 	schemaValue := reflect.ValueOf(schema)
-	schemaType := reflect.TypeOf(schema)
-	schemaLength := schemaValue.NumField()
+	fields := schemaFields(schemaValue.Type())
 
-	if schemaLength < 1 {
+	if len(fields) < 1 {
 		return nil, errors.New("expected at least one attribute in schema")
 	}
 
+	keyCounts := make(map[types.KeyType]int)
+	for _, field := range fields {
+		keyCounts[field.keyType]++
+	}
+
+	if keyCounts[types.KeyTypeHash] > 1 || keyCounts[types.KeyTypeRange] > 1 {
+		return nil, errors.New("expected at most one hash key and one range key in schema")
+	}
+
 	var attributes []types.AttributeDefinition
-	for i := 0; i < schemaLength; i++ {
-		fieldName := schemaType.Field(i).Name
+	var keySchema []types.KeySchemaElement
 
-		attributeType, err := toAttributeType(schemaValue.Field(i).Interface())
+	hash, rangeKey := keyFields(fields)
+	keyTypes := []types.KeyType{types.KeyTypeHash, types.KeyTypeRange}
+	for i, field := range []*schemaField{hash, rangeKey} {
+		if field == nil {
+			continue
+		}
+
+		attributeType, err := toAttributeType(*field, schemaValue.Type().FieldByIndex(field.index).Type)
 		if err != nil {
 			return nil, err
 		}
 
-		fieldAttribute := types.AttributeDefinition{
-			AttributeName: &fieldName,
+		attributeName := field.name
+		attributes = append(attributes, types.AttributeDefinition{
+			AttributeName: &attributeName,
 			AttributeType: attributeType,
-		}
-
-		attributes = append(attributes, fieldAttribute)
-	}
-
-	var keySchema []types.KeySchemaElement
-
-	//goland:noinspection GoNilness
-	keySchema = append(keySchema, types.KeySchemaElement{
-		AttributeName: attributes[0].AttributeName,
-		KeyType:       types.KeyTypeHash,
-	})
+		})
 
-	//goland:noinspection GoNilness
-	if len(attributes) > 1 {
 		keySchema = append(keySchema, types.KeySchemaElement{
-			AttributeName: attributes[1].AttributeName,
-			KeyType:       types.KeyTypeRange,
+			AttributeName: &attributeName,
+			KeyType:       keyTypes[i],
 		})
 	}
 
 	var provision int64 = 1
 
-	output, err := dbClient.CreateTable(dbCtx, &dynamodb.CreateTableInput{
+	output, err := c.db.CreateTable(ctx, &dynamodb.CreateTableInput{
 		TableName:            &name,
 		AttributeDefinitions: attributes,
 		KeySchema:            keySchema,
@@ -80,13 +111,26 @@ func CreateTable(name string, schema interface{}) (*Table, error) {
 		return nil, err
 	}
 
-	return &Table{*output.TableDescription.TableName, schema, buildProjection(schema)}, nil
+	return c.newTable(*output.TableDescription.TableName, schema, keySchema), nil
 }
 
 // ListTables is a simple operation which returns the list of
 // all table names that are available to you.
-func ListTables() ([]string, error) {
-	output, err := dbClient.ListTables(dbCtx, &dynamodb.ListTablesInput{})
+func ListTables() ([]string, error) { return defaultClient.ListTables() }
+
+// ListTablesCtx is the same as ListTables, listing the tables with the
+// context.
+func ListTablesCtx(ctx context.Context) ([]string, error) {
+	return defaultClient.ListTablesCtx(ctx)
+}
+
+// ListTables lists the names of the tables available to the Client.
+func (c *Client) ListTables() ([]string, error) { return c.ListTablesCtx(dbCtx) }
+
+// ListTablesCtx is the same as Client.ListTables, listing the tables
+// with the context.
+func (c *Client) ListTablesCtx(ctx context.Context) ([]string, error) {
+	output, err := c.db.ListTables(ctx, &dynamodb.ListTablesInput{})
 	if err != nil {
 		return nil, fmt.Errorf("error listing tables: %v", err)
 	}
@@ -0,0 +1,50 @@
+package dynago
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"testing"
+)
+
+func TestTable_key(t *testing.T) {
+	type person struct {
+		Name string `dynago:"name,range"`
+		Id   int    `dynago:"id,hash"`
+		Age  int
+	}
+
+	described := Table{Name: "Test", Schema: person{}, HashKey: "id", RangeKey: "name"}
+	constructed := Table{Name: "Test", Schema: person{}}
+	want := testItem(map[string]interface{}{"id": 1, "name": "abc"})
+
+	tests := []struct {
+		name  string
+		table Table
+		item  interface{}
+		want  map[string]types.AttributeValue
+	}{
+		{"struct", described, person{"abc", 1, 2}, want},
+		{"map", described, map[string]interface{}{"id": 1, "name": "abc", "Age": 2}, want},
+		{"struct without key schema", constructed, person{"abc", 1, 2}, want},
+		{"map without key schema", constructed, map[string]interface{}{"id": 1, "name": "abc"}, want},
+		{
+			"partial map without key schema",
+			constructed,
+			map[string]interface{}{"id": 1},
+			testItem(map[string]interface{}{"id": 1}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.table.key(tt.item)
+			if err != nil {
+				t.Fatalf("key() err = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("key() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package test
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/eyebrow-fish/dynago"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -15,7 +17,49 @@ func (s *CreateTableSuite) TestHappyPath() {
 
 	assert.NoError(s.T(), err)
 	assert.NotNil(s.T(), table)
-	assert.Equal(s.T(), &dynago.Table{Name: "testTable", Schema: testTable{}, Projection: "Id,FullName"}, table)
+	assert.Equal(s.T(), dynago.Table{
+		Name:       "testTable",
+		Schema:     testTable{},
+		Projection: "Id,FullName",
+		HashKey:    "Id",
+		RangeKey:   "FullName",
+	}, exported(table))
+}
+
+func (s *CreateTableSuite) TestTagged() {
+	table, err := dynago.CreateTable("testTable", testPerson{})
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), dynago.Table{
+		Name:       "testTable",
+		Schema:     testPerson{},
+		Projection: "name,id,visits",
+		HashKey:    "id",
+		RangeKey:   "name",
+	}, exported(table))
+}
+
+func (s *CreateTableSuite) TestEmbeddedPointer() {
+	table, err := dynago.CreateTable("testTable", testEmbedded{})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "id", table.HashKey)
+	assert.Equal(s.T(), "name", table.RangeKey)
+
+	item := testEmbedded{&EmbeddedKey{123}, "abc"}
+	_, err = table.Put(item)
+	assert.NoError(s.T(), err)
+
+	got, err := table.Get(item)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), item, got)
+}
+
+func (s *CreateTableSuite) TestDuplicateHash() {
+	_, err := dynago.CreateTable("testTable", struct {
+		Id    int `dynago:",hash"`
+		Other int `dynago:",hash"`
+	}{})
+	assert.Error(s.T(), err)
 }
 
 func (s *CreateTableSuite) TestDuplicate() {
@@ -32,8 +76,8 @@ func (s *CreateTableSuite) TestNoHash() {
 func TestCreateTable(t *testing.T) { suite.Run(t, new(CreateTableSuite)) }
 
 func TestListTables(t *testing.T) {
-	process := SetupLocalDynamo()
-	defer func() { panicOnError(process.Kill()) }()
+	_, process := SetupDynamo()
+	defer StopDynamo(process)
 
 	_, _ = dynago.CreateTable("testTable1", testTable{})
 	_, _ = dynago.CreateTable("testTable2", testTable{})
@@ -43,3 +87,42 @@ func TestListTables(t *testing.T) {
 
 	assert.Equal(t, []string{"testTable1", "testTable2"}, tableNames)
 }
+
+type ClientSuite struct{ DynamoSuite }
+
+func (s *ClientSuite) TestHappyPath() {
+	client := dynago.NewClientWithAPI(s.api)
+
+	created, err := client.CreateTable("testTable", testTable{})
+	assert.NoError(s.T(), err)
+
+	fetched, err := client.NewTable("testTable", testTable{})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), created, fetched)
+
+	tableNames, err := client.ListTables()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"testTable"}, tableNames)
+}
+
+func (s *ClientSuite) TestBoundTables() {
+	table, _ := dynago.CreateTable("testTable", testTable{})
+
+	unreachable := testOptions
+	unreachable.EndpointResolver = dynamodb.EndpointResolverFromURL("http://localhost:1")
+	unreachable.Retryer = aws.NopRetryer{}
+	dynago.UpdateOptions(unreachable)
+
+	_, err := dynago.ListTables()
+	assert.Error(s.T(), err)
+
+	item := testTable{123, "abc"}
+	_, err = table.Put(item)
+	assert.NoError(s.T(), err)
+
+	fetched, err := table.Get(item)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), item, fetched)
+}
+
+func TestClient(t *testing.T) { suite.Run(t, new(ClientSuite)) }
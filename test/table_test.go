@@ -1,9 +1,13 @@
 package test
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/eyebrow-fish/dynago"
+	"github.com/eyebrow-fish/dynago/dynagotest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -26,6 +30,136 @@ func (s *NewTableSuite) TestNoTable() {
 
 func TestNewTable(t *testing.T) { suite.Run(t, new(NewTableSuite)) }
 
+type GetSuite struct{ DynamoSuite }
+
+func (s *GetSuite) TestHappyPath() {
+	table, _ := dynago.CreateTable("testTable", testTable{})
+
+	item := testTable{123, "abc"}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	got, err := table.Get(testTable{Id: 123, FullName: "abc"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), item, got)
+}
+
+func (s *GetSuite) TestConsistent() {
+	table, _ := dynago.CreateTable("testTable", testTable{})
+
+	item := testTable{123, "abc"}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	got, err := table.GetConsistent(testTable{Id: 123, FullName: "abc"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), item, got)
+}
+
+func (s *GetSuite) TestNotFound() {
+	table, _ := dynago.CreateTable("testTable", testTable{})
+
+	_, err := table.Get(testTable{Id: 123, FullName: "abc"})
+
+	var notFound *dynago.ItemNotFoundError
+	assert.True(s.T(), errors.As(err, &notFound))
+	assert.Equal(s.T(), "testTable", notFound.TableName)
+}
+
+func (s *GetSuite) TestNested() {
+	table, _ := dynago.CreateTable("testTable", testProfile{})
+
+	item := testProfile{
+		testPerson: testPerson{Name: "abc", Id: 123},
+		Home:       testAddress{"Main Street", 1},
+		Previous:   []testAddress{{"Side Street", 2}},
+		Contacts:   map[string]testAddress{"work": {"Office Lane", 3}},
+	}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	got, err := table.Get(testProfile{testPerson: testPerson{Name: "abc", Id: 123}})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), item, got)
+}
+
+func (s *GetSuite) TestNumbers() {
+	table, _ := dynago.CreateTable("testTable", testMeasurement{})
+
+	item := testMeasurement{"abc", 35, -1.5, "123456789012345678901234567890.5"}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	got, err := table.Get(testMeasurement{Id: "abc"})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), item, got)
+}
+
+func (s *GetSuite) TestOptional() {
+	table, _ := dynago.CreateTable("testTable", testOptional{})
+
+	_, err := table.Put(testOptional{Id: 1})
+	assert.NoError(s.T(), err)
+
+	got, err := table.Get(testOptional{Id: 1})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), testOptional{Id: 1}, got)
+
+	nickname := "abc"
+	_, err = table.Put(testOptional{Id: 2, Nickname: &nickname})
+	assert.NoError(s.T(), err)
+
+	got, err = table.Get(testOptional{Id: 2})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "abc", *got.(testOptional).Nickname)
+
+	exists, err := table.Scan(dynago.Exists("nickname"))
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), exists, 1)
+}
+
+func TestGet(t *testing.T) { suite.Run(t, new(GetSuite)) }
+
+type GetManySuite struct{ DynamoSuite }
+
+func (s *GetManySuite) TestHappyPath() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	var items, keys []interface{}
+	for i := 0; i < 150; i++ {
+		items = append(items, testPerson{"abc", i, i})
+		keys = append(keys, testPerson{Name: "abc", Id: 149 - i})
+	}
+
+	_, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+
+	got, err := table.GetMany(keys)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 150, len(got))
+	assert.Equal(s.T(), testPerson{"abc", 149, 149}, got[0])
+	assert.Equal(s.T(), testPerson{"abc", 0, 0}, got[149])
+}
+
+func (s *GetManySuite) TestMissing() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	item := testPerson{"abc", 1, 1}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	missingKey := map[string]interface{}{"id": 2, "name": "abc"}
+	got, err := table.GetMany([]interface{}{missingKey, item, item})
+	assert.Equal(s.T(), []interface{}{nil, item, item}, got)
+
+	var missing *dynago.MissingItemsError
+	assert.True(s.T(), errors.As(err, &missing))
+	assert.Equal(s.T(), []int{0}, missing.Indexes)
+	assert.Equal(s.T(), []interface{}{missingKey}, missing.Keys)
+}
+
+func TestGetMany(t *testing.T) { suite.Run(t, new(GetManySuite)) }
+
 type QuerySuite struct{ DynamoSuite }
 
 func (s *QuerySuite) TestQueryWithExpr() {
@@ -106,6 +240,126 @@ func (s *QuerySuite) TestLimit1() {
 	assert.Equal(s.T(), testTable{123, "abc"}, value)
 }
 
+func (s *QuerySuite) TestBeginsWith() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	items := []interface{}{testPerson{"abc", 1, 1}, testPerson{"abd", 1, 2}, testPerson{"bcd", 1, 3}}
+	_, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+
+	testValue, err := table.Query(dynago.Eq("id", dynago.N(1)).And(dynago.BeginsWith("name", "ab")))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), items[:2], testValue)
+}
+
+func (s *QuerySuite) TestFilter() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	items := []interface{}{testPerson{"abc", 1, 1}, testPerson{"abd", 1, 2}, testPerson{"bcd", 1, 3}}
+	_, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+
+	testValue, err := table.Query(
+		dynago.Gte("visits", dynago.N(2)).
+			And(dynago.Eq("id", dynago.N(1))).
+			And(dynago.Lt("name", dynago.S("bcd"))),
+	)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), items[1:2], testValue)
+}
+
+func (s *QuerySuite) TestWithFilter() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	items := []interface{}{testPerson{"abc", 1, 1}, testPerson{"abd", 1, 2}, testPerson{"bcd", 1, 3}}
+	_, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+
+	testValue, err := table.QueryWithFilter(
+		dynago.Eq("id", dynago.N(1)),
+		dynago.Eq("visits", dynago.N(1)).Or(dynago.Eq("visits", dynago.N(3))),
+	)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []interface{}{items[0], items[2]}, testValue)
+}
+
+func (s *QuerySuite) TestNoKeyCondition() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	_, err := table.Query(dynago.Eq("visits", dynago.N(1)))
+	assert.Error(s.T(), err)
+}
+
+func (s *QuerySuite) TestTimes() {
+	table, _ := dynago.CreateTable("testTable", testEvent{})
+
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var items []interface{}
+	for i := 0; i < 12; i++ {
+		at := start.Add(time.Duration(i) * 5 * time.Hour) // Crosses days, where unpadded times sort wrong
+		items = append(items, testEvent{"abc", at, at.Add(24 * time.Hour).Truncate(time.Second)})
+	}
+
+	_, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+
+	testValue, err := table.Query(dynago.Eq("Source", dynago.S("abc")).
+		And(dynago.Bt("At", dynago.Time(start.Add(time.Hour)), dynago.Time(start.Add(30*time.Hour)))))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), items[1:7], testValue)
+}
+
+func (s *QuerySuite) TestCanceled() {
+	table, _ := dynago.CreateTable("testTable", testTable{})
+	_, err := table.Put(testTable{123, "abc"})
+	assert.NoError(s.T(), err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testValue, err := table.QueryCtx(ctx, dynago.Eq("Id", dynago.N(123)))
+	assert.ErrorIs(s.T(), err, context.Canceled)
+	assert.Nil(s.T(), testValue)
+}
+
+func (s *QuerySuite) TestIter() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+	if emulator, ok := s.api.(*dynagotest.Emulator); ok {
+		emulator.PageSize = 1
+	}
+
+	items := []interface{}{testPerson{"abc", 1, 1}, testPerson{"abd", 1, 2}, testPerson{"abe", 1, 3}}
+	_, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+
+	iter := table.QueryIter(dynago.Eq("id", dynago.N(1)))
+	defer iter.Close()
+
+	var queried []interface{}
+	for iter.Next() {
+		queried = append(queried, iter.Item())
+		if len(queried) == 2 {
+			break
+		}
+	}
+
+	assert.NoError(s.T(), iter.Err())
+	assert.Equal(s.T(), items[:2], queried)
+}
+
+func (s *QuerySuite) TestLimitBeyondItems() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	items := []interface{}{testPerson{"abc", 1, 1}, testPerson{"abd", 1, 2}, testPerson{"abe", 1, 3}}
+	_, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+
+	queried, err := table.Query(dynago.Eq("id", dynago.N(1)).And(dynago.Gt("visits", dynago.N(1))).WithLimit(5))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), items[1:], queried)
+}
+
 func TestQuery(t *testing.T) { suite.Run(t, new(QuerySuite)) }
 
 type ScanSuite struct{ DynamoSuite }
@@ -157,10 +411,77 @@ func (s *ScanSuite) TestLimit1() {
 	assert.Equal(s.T(), testTable{123, "abc"}, value1)
 }
 
+func (s *ScanSuite) TestChan() {
+	table, _ := dynago.CreateTable("testTable", testTable{})
+
+	items := []interface{}{testTable{123, "abc"}, testTable{456, "def"}}
+	_, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+
+	iter := table.ScanIter(dynago.All())
+	defer iter.Close()
+
+	var scanned []interface{}
+	for item := range iter.Chan() {
+		scanned = append(scanned, item)
+	}
+
+	assert.NoError(s.T(), iter.Err())
+	assert.ElementsMatch(s.T(), items, scanned)
+}
+
+func (s *ScanSuite) TestOrNot() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	var items []interface{}
+	for i := 0; i < 5; i++ {
+		items = append(items, testPerson{"abc", i, i})
+	}
+
+	_, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+
+	scan, err := table.Scan(
+		dynago.Eq("id", dynago.N(1)).
+			Or(dynago.Gt("visits", dynago.N(2))).
+			And(dynago.Not(dynago.Eq("name", dynago.S("def")))),
+	)
+	assert.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), []interface{}{items[1], items[3], items[4]}, scan)
+}
+
 func TestScan(t *testing.T) { suite.Run(t, new(ScanSuite)) }
 
 type PutSuite struct{ DynamoSuite }
 
+func (s *PutSuite) TestNotExists() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	_, err := table.PutWithCondition(dynago.NotExists("id"), testPerson{"abc", 1, 1})
+	assert.NoError(s.T(), err)
+
+	_, err = table.PutWithCondition(dynago.NotExists("id"), testPerson{"abc", 1, 2})
+	assert.Error(s.T(), err)
+}
+
+func (s *PutSuite) TestFunctions() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	item := testPerson{"abc", 1, 1}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	_, err = table.PutWithCondition(
+		dynago.In("visits", dynago.N(1), dynago.N(2)).
+			And(dynago.Size("name").Eq(dynago.N(3))).
+			And(dynago.AttributeType("name", "S")).
+			And(dynago.Contains("name", dynago.S("b"))).
+			And(dynago.Exists("visits")),
+		testPerson{"abc", 1, 3},
+	)
+	assert.NoError(s.T(), err)
+}
+
 func (s *PutSuite) ConditionFails() {
 	table, _ := dynago.CreateTable("testTable", testTable{})
 
@@ -179,6 +500,243 @@ func (s *PutSuite) ConditionPasses() {
 
 func TestPut(t *testing.T) { suite.Run(t, new(PutSuite)) }
 
+type UpdateSuite struct{ DynamoSuite }
+
+func (s *UpdateSuite) TestHappyPath() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	item := testPerson{"abc", 123, 1}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	updated, err := table.Update(
+		item,
+		dynago.Set("visits", dynago.Plus(dynago.Field("visits"), dynago.N(2))),
+		dynago.All(),
+	)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), testPerson{"abc", 123, 3}, updated)
+}
+
+func (s *UpdateSuite) TestSets() {
+	table, _ := dynago.CreateTable("testTable", testTagged{})
+
+	item := testTagged{1, dynago.StringSet{"a", "b"}, []string{"x", "x"}}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	_, err = table.Update(item, dynago.Add("Labels", dynago.SS([]string{"c"})), dynago.All())
+	assert.NoError(s.T(), err)
+
+	updated, err := table.Update(
+		item,
+		dynago.Delete("Labels", dynago.SS([]string{"a"})),
+		dynago.Contains("Labels", dynago.S("a")),
+	)
+	assert.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), dynago.StringSet{"b", "c"}, updated.(testTagged).Labels)
+	assert.Equal(s.T(), []string{"x", "x"}, updated.(testTagged).Tags)
+}
+
+func (s *UpdateSuite) TestReturnAllOld() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	item := testPerson{"abc", 123, 1}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	updated, err := table.Update(
+		item,
+		dynago.Remove("visits").WithReturnValues(dynago.ReturnAllOld),
+		dynago.All(),
+	)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), item, updated)
+
+	got, err := table.Get(item)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), testPerson{"abc", 123, 0}, got)
+}
+
+func (s *UpdateSuite) TestReturnNone() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	updated, err := table.Update(
+		testPerson{Name: "abc", Id: 123},
+		dynago.Set("visits", dynago.N(1)).WithReturnValues(dynago.ReturnNone),
+		dynago.All(),
+	)
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), updated)
+}
+
+func (s *UpdateSuite) TestConditionFails() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	item := testPerson{"abc", 123, 1}
+	_, err := table.Update(item, dynago.Set("visits", dynago.N(1)), dynago.Gte("id", dynago.N(124)))
+	assert.Error(s.T(), err)
+}
+
+func (s *UpdateSuite) TestNestedPaths() {
+	table, _ := dynago.CreateTable("testTable", testDocument{})
+
+	item := testDocument{
+		Id:      1,
+		Address: map[string]interface{}{"City": "Amsterdam", "Street": "Dam"},
+		Tags:    []interface{}{"a", "b"},
+	}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	updated, err := table.Update(
+		item,
+		dynago.Set(dynago.Path("Address", "City"), dynago.S("Utrecht")).Remove(dynago.Path("Tags").Index(0)),
+		dynago.Eq("Address.City", dynago.S("Amsterdam")).And(dynago.Eq(dynago.Path("Tags").Index(1), dynago.S("b"))),
+	)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), testDocument{
+		Id:      1,
+		Address: map[string]interface{}{"City": "Utrecht", "Street": "Dam"},
+		Tags:    []interface{}{"b"},
+	}, updated)
+
+	table.Projection = "Id,Address.City"
+	got, err := table.Get(item)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), testDocument{Id: 1, Address: map[string]interface{}{"City": "Utrecht"}}, got)
+}
+
+func TestUpdate(t *testing.T) { suite.Run(t, new(UpdateSuite)) }
+
+type PutAllSuite struct{ DynamoSuite }
+
+func (s *PutAllSuite) TestHappyPath() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+	table.BatchConcurrency = 3
+
+	var items []interface{}
+	for i := 0; i < 60; i++ {
+		items = append(items, testPerson{"abc", i, i})
+	}
+
+	put, err := table.PutAll(items)
+	assert.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), items, put)
+
+	scanned, err := table.ScanAll()
+	assert.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), items, scanned)
+}
+
+func (s *PutAllSuite) TestMissingTable() {
+	table := dynago.Table{Name: "missingTable", Schema: testPerson{}}
+
+	items := []interface{}{testPerson{"abc", 1, 1}, testPerson{"def", 2, 2}}
+	put, err := table.PutAll(items)
+	assert.Empty(s.T(), put)
+
+	var batchErr *dynago.BatchWriteError
+	assert.True(s.T(), errors.As(err, &batchErr))
+	assert.Equal(s.T(), 2, len(batchErr.Failures))
+	assert.Equal(s.T(), items[1], batchErr.Failures[1].Item)
+}
+
+func (s *PutAllSuite) TestCanceled() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	items := []interface{}{testPerson{"abc", 1, 1}, testPerson{"def", 2, 2}}
+	put, err := table.PutAllCtx(ctx, items)
+	assert.Empty(s.T(), put)
+
+	var batchErr *dynago.BatchWriteError
+	assert.True(s.T(), errors.As(err, &batchErr))
+	assert.ErrorIs(s.T(), batchErr.Failures[0].Err, context.Canceled)
+
+	scanned, err := table.ScanAll()
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), scanned)
+}
+
+func TestPutAll(t *testing.T) { suite.Run(t, new(PutAllSuite)) }
+
+type TxSuite struct{ DynamoSuite }
+
+func (s *TxSuite) TestHappyPath() {
+	people, _ := dynago.CreateTable("people", testPerson{})
+	others, _ := dynago.CreateTable("others", testTable{})
+
+	person := testPerson{"abc", 1, 10}
+	_, err := people.Put(person)
+	assert.NoError(s.T(), err)
+
+	err = dynago.NewTx().
+		Update(people, person, dynago.Add("visits", dynago.N(-5)), dynago.Gte("visits", dynago.N(5))).
+		Put(others, testTable{1, "abc"}, dynago.All()).
+		ConditionCheck(people, person, dynago.Gte("visits", dynago.N(10))).
+		Execute()
+	assert.Error(s.T(), err, "an item can only be in one operation")
+
+	err = dynago.NewTx().
+		Update(people, person, dynago.Add("visits", dynago.N(-5)), dynago.Gte("visits", dynago.N(5))).
+		Put(others, testTable{1, "abc"}, dynago.All()).
+		Execute()
+	assert.NoError(s.T(), err)
+
+	got, err := dynago.TransactGet{}.
+		Get(people, person).
+		Get(others, testTable{1, "abc"}).
+		Get(others, testTable{2, "def"}).
+		Execute()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []interface{}{testPerson{"abc", 1, 5}, testTable{1, "abc"}, nil}, got)
+}
+
+func (s *TxSuite) TestCanceled() {
+	people, _ := dynago.CreateTable("people", testPerson{})
+	others, _ := dynago.CreateTable("others", testTable{})
+
+	person := testPerson{"abc", 1, 3}
+	_, err := people.Put(person)
+	assert.NoError(s.T(), err)
+
+	err = dynago.NewTx().
+		Put(others, testTable{1, "abc"}, dynago.All()).
+		Update(people, person, dynago.Add("visits", dynago.N(-5)), dynago.Gte("visits", dynago.N(5))).
+		Execute()
+
+	var canceled *dynago.TxCanceledError
+	assert.True(s.T(), errors.As(err, &canceled))
+	assert.Equal(s.T(), []dynago.TxCancellationReason{{
+		Index:   1,
+		Code:    dynago.ReasonConditionalCheckFailed,
+		Message: "The conditional request failed",
+		Item:    person,
+	}}, canceled.Reasons)
+
+	remaining, err := others.ScanAll()
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), remaining)
+}
+
+func (s *TxSuite) TestIdempotent() {
+	people, _ := dynago.CreateTable("people", testPerson{})
+
+	person := testPerson{"abc", 1, 0}
+	tx := dynago.NewTx().Update(people, person, dynago.Add("visits", dynago.N(1)), dynago.All())
+	assert.NoError(s.T(), tx.Execute())
+	assert.NoError(s.T(), tx.Execute())
+
+	got, err := people.Get(person)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), testPerson{"abc", 1, 1}, got)
+}
+
+func TestTx(t *testing.T) { suite.Run(t, new(TxSuite)) }
+
 type DeleteSuite struct{ DynamoSuite }
 
 func (s DeleteSuite) TestDeleteItem() {
@@ -228,11 +786,154 @@ func (s DeleteSuite) TestDelete() {
 	assert.Equal(s.T(), 0, len(remaining))
 }
 
+func (s DeleteSuite) TestDeleteItemByKey() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	item := testPerson{"abc", 123, 4}
+	_, err := table.Put(item)
+	assert.NoError(s.T(), err)
+
+	key := map[string]interface{}{"id": 123, "name": "abc"}
+	deleted, err := table.DeleteItem(key)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), key, deleted)
+
+	remaining, err := table.ScanAll()
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), remaining)
+}
+
+func (s DeleteSuite) TestDeleteNonKeyAttributes() {
+	table, _ := dynago.CreateTable("testTable", testPerson{})
+
+	item1 := testPerson{"abc", 123, 4}
+	_, err := table.Put(item1)
+	assert.NoError(s.T(), err)
+
+	item2 := testPerson{"def", 123, 5}
+	_, err = table.Put(item2)
+	assert.NoError(s.T(), err)
+
+	deleted, err := table.DeleteItem(item1)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), item1, deleted)
+
+	deletedAll, err := table.Delete(dynago.Eq("id", dynago.N(123)))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []interface{}{item2}, deletedAll)
+
+	remaining, err := table.ScanAll()
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), remaining)
+}
+
 func TestDelete(t *testing.T) {
 	suite.Run(t, new(DeleteSuite))
 }
 
+type TypedTableSuite struct{ DynamoSuite }
+
+func (s *TypedTableSuite) TestHappyPath() {
+	table, err := dynago.CreateTypedTable[testPerson]("testTable")
+	assert.NoError(s.T(), err)
+
+	people := []testPerson{{"abc", 1, 1}, {"abd", 1, 2}, {"abc", 2, 3}}
+	_, err = table.PutAll(people)
+	assert.NoError(s.T(), err)
+
+	got, err := table.Get(testPerson{Name: "abd", Id: 1})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), people[1], got)
+
+	queried, err := table.Query(dynago.Eq("id", dynago.N(1)))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), people[:2], queried)
+
+	updated, err := table.Update(people[2], dynago.Add("visits", dynago.N(1)), dynago.All())
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 4, updated.Visits)
+
+	deleted, err := table.Delete(dynago.Eq("id", dynago.N(1)))
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), deleted, 2)
+
+	scanned, err := table.ScanAll()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []testPerson{{"abc", 2, 4}}, scanned)
+}
+
+func (s *TypedTableSuite) TestNotFound() {
+	table, _ := dynago.CreateTypedTable[testPerson]("testTable")
+
+	got, err := table.Get(testPerson{Name: "abc", Id: 1})
+
+	var notFound *dynago.ItemNotFoundError
+	assert.True(s.T(), errors.As(err, &notFound))
+	assert.Equal(s.T(), testPerson{}, got)
+}
+
+func TestTypedTable(t *testing.T) { suite.Run(t, new(TypedTableSuite)) }
+
 type testTable struct {
 	Id       int
 	FullName string
 }
+
+// EmbeddedKey is exported, since the fields of nil embedded pointers to
+// unexported structs cannot be decoded.
+type EmbeddedKey struct {
+	Id int `dynago:"id,hash"`
+}
+
+type testEmbedded struct {
+	*EmbeddedKey
+	Name string `dynago:"name,range"`
+}
+
+type testPerson struct {
+	Name   string `dynago:"name,range"`
+	Id     int    `dynago:"id,hash"`
+	Visits int    `dynago:"visits,omitempty"`
+}
+
+type testAddress struct {
+	Street string
+	Number int
+}
+
+type testProfile struct {
+	testPerson
+	Home     testAddress
+	Previous []testAddress
+	Contacts map[string]testAddress
+}
+
+type testMeasurement struct {
+	Id      string `dynago:",hash"`
+	Age     uint8
+	Value   float64
+	Precise dynago.Number
+}
+
+type testOptional struct {
+	Id       int     `dynago:"id,hash"`
+	Nickname *string `dynago:"nickname,omitempty"`
+}
+
+type testEvent struct {
+	Source  string    `dynago:",hash"`
+	At      time.Time `dynago:",range"`
+	Expires time.Time `dynago:",unix"`
+}
+
+type testTagged struct {
+	Id     int `dynago:",hash"`
+	Labels dynago.StringSet
+	Tags   []string
+}
+
+type testDocument struct {
+	Id      int `dynago:",hash"`
+	Address map[string]interface{}
+	Tags    []interface{}
+}
@@ -11,15 +11,38 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/eyebrow-fish/dynago"
+	"github.com/eyebrow-fish/dynago/dynagotest"
 )
 
 type DynamoSuite struct {
 	suite.Suite
+	api     dynago.API
 	process *os.Process
 }
 
-func (s *DynamoSuite) SetupTest()    { s.process = SetupLocalDynamo() }
-func (s *DynamoSuite) TearDownTest() { panicOnError(s.process.Kill()) }
+func (s *DynamoSuite) SetupTest()    { s.api, s.process = SetupDynamo() }
+func (s *DynamoSuite) TearDownTest() { StopDynamo(s.process) }
+
+// SetupDynamo makes dynago use a new, empty dynagotest.Emulator, or
+// DynamoDB Local when the DYNAGO_LOCAL environment variable is set.
+func SetupDynamo() (dynago.API, *os.Process) {
+	if os.Getenv("DYNAGO_LOCAL") != "" {
+		process := SetupLocalDynamo()
+		return dynamodb.New(testOptions), process
+	}
+
+	api := dynagotest.New()
+	dynago.UpdateAPI(api)
+
+	return api, nil
+}
+
+// StopDynamo stops DynamoDB Local, if SetupDynamo started it.
+func StopDynamo(process *os.Process) {
+	if process != nil {
+		panicOnError(process.Kill())
+	}
+}
 
 func SetupLocalDynamo() *os.Process {
 	homeDir, err := os.UserHomeDir()
@@ -43,6 +66,19 @@ func SetupLocalDynamo() *os.Process {
 	return command.Process
 }
 
+// exported is the Table without its Client, which cannot be compared
+// outside of dynago.
+func exported(table *dynago.Table) dynago.Table {
+	return dynago.Table{
+		Name:             table.Name,
+		Schema:           table.Schema,
+		Projection:       table.Projection,
+		HashKey:          table.HashKey,
+		RangeKey:         table.RangeKey,
+		BatchConcurrency: table.BatchConcurrency,
+	}
+}
+
 func panicOnError(err error) {
 	if err == nil {
 		return
@@ -0,0 +1,89 @@
+package dynago
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// timeEncoding is how a time.Time is stored, chosen by the options of
+// the field's tag.
+//
+//  type Session struct {
+//    Id      string
+//    Started time.Time                        // "2021-06-01T12:00:00.000000000Z"
+//    Expires time.Time `dynago:",unix"`      // 1622548800
+//    Seen    time.Time `dynago:",unixmilli"` // 1622548800000
+//  }
+type timeEncoding int
+
+const (
+	// timeRFC3339 stores times as RFC 3339 strings in UTC, with all nine
+	// digits of the fraction, so that they sort in chronological order.
+	timeRFC3339 timeEncoding = iota
+	// timeUnix stores times as the seconds since the Unix epoch, which
+	// is what DynamoDb expects of TTL attributes.
+	timeUnix
+	// timeUnixMilli stores times as the milliseconds since the Unix epoch.
+	timeUnixMilli
+)
+
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+func encodeTime(t time.Time, encoding timeEncoding) types.AttributeValue {
+	switch encoding {
+	case timeUnix:
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.Unix(), 10)}
+	case timeUnixMilli:
+		millis := t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(millis, 10)}
+	default:
+		return &types.AttributeValueMemberS{Value: t.UTC().Format(timeLayout)}
+	}
+}
+
+// decodeTime decodes both strings and numbers, whatever the encoding,
+// so that changing the encoding of a field keeps older items readable.
+// Numbers are taken as seconds, unless the encoding is timeUnixMilli.
+func decodeTime(attribute types.AttributeValue, encoding timeEncoding) (time.Time, error) {
+	switch a := attribute.(type) {
+	case *types.AttributeValueMemberS:
+		return time.Parse(time.RFC3339Nano, a.Value)
+	case *types.AttributeValueMemberN:
+		n, err := parseInt(a.Value, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if encoding == timeUnixMilli {
+			return time.Unix(n/1000, n%1000*int64(time.Millisecond)).UTC(), nil
+		}
+
+		return time.Unix(n, 0).UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("cannot decode %T into %v", attribute, timeType)
+	}
+}
+
+// decodeDuration decodes a number of nanoseconds, the way durations
+// are stored, or a string such as "1h30m".
+func decodeDuration(attribute types.AttributeValue) (time.Duration, error) {
+	switch a := attribute.(type) {
+	case *types.AttributeValueMemberS:
+		return time.ParseDuration(a.Value)
+	case *types.AttributeValueMemberN:
+		n, err := parseInt(a.Value, 64)
+
+		return time.Duration(n), err
+	default:
+		return 0, fmt.Errorf("cannot decode %T into %v", attribute, durationType)
+	}
+}
@@ -0,0 +1,113 @@
+package dynago
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_times(t *testing.T) {
+	type session struct {
+		Id      string
+		Started time.Time
+		Expires time.Time  `dynago:",unix"`
+		Seen    *time.Time `dynago:",unixmilli"`
+		Ended   *time.Time `dynago:",unix"`
+		Timeout time.Duration
+	}
+
+	started := time.Date(2021, 6, 1, 12, 0, 0, 500, time.UTC)
+	seen := started.Add(1500 * time.Millisecond).Truncate(time.Millisecond)
+	want := session{
+		Id:      "abc",
+		Started: started,
+		Expires: started.Add(time.Hour).Truncate(time.Second),
+		Seen:    &seen,
+		Timeout: 90 * time.Second,
+	}
+
+	item, err := buildItem(want)
+	if err != nil {
+		t.Fatalf("buildItem() error = %v", err)
+	}
+
+	wantItem := map[string]types.AttributeValue{
+		"Id":      &types.AttributeValueMemberS{Value: "abc"},
+		"Started": &types.AttributeValueMemberS{Value: "2021-06-01T12:00:00.000000500Z"},
+		"Expires": &types.AttributeValueMemberN{Value: "1622552400"},
+		"Seen":    &types.AttributeValueMemberN{Value: "1622548801500"},
+		"Ended":   &types.AttributeValueMemberNULL{Value: true},
+		"Timeout": &types.AttributeValueMemberN{Value: "90000000000"},
+	}
+	if !reflect.DeepEqual(item, wantItem) {
+		t.Errorf("buildItem() = %v, want %v", item, wantItem)
+	}
+
+	got, err := constructItem(item, session{})
+	if err != nil {
+		t.Fatalf("constructItem() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("constructItem() = %v, want %v", got, want)
+	}
+}
+
+func Test_decodeTime(t *testing.T) {
+	want := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		attribute types.AttributeValue
+		encoding  timeEncoding
+		wantErr   bool
+	}{
+		{"rfc3339", &types.AttributeValueMemberS{Value: "2021-06-01T14:00:00+02:00"}, timeRFC3339, false},
+		{"unix", &types.AttributeValueMemberN{Value: "1622548800"}, timeRFC3339, false},
+		{"unixmilli", &types.AttributeValueMemberN{Value: "1622548800000"}, timeUnixMilli, false},
+		{"string into unix", &types.AttributeValueMemberS{Value: "2021-06-01T12:00:00Z"}, timeUnix, false},
+		{"invalid", &types.AttributeValueMemberS{Value: "yesterday"}, timeRFC3339, true},
+		{"bool", &types.AttributeValueMemberBOOL{Value: true}, timeRFC3339, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeTime(tt.attribute, tt.encoding)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("decodeTime() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(want) {
+				t.Errorf("decodeTime() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_decodeDuration(t *testing.T) {
+	for _, attribute := range []types.AttributeValue{
+		&types.AttributeValueMemberN{Value: "5400000000000"},
+		&types.AttributeValueMemberS{Value: "1h30m"},
+	} {
+		got, err := decodeDuration(attribute)
+		if err != nil || got != 90*time.Minute {
+			t.Errorf("decodeDuration(%v) = %v, %v, want 1h30m", attribute, got, err)
+		}
+	}
+}
+
+func Test_timeEncoding_invalid(t *testing.T) {
+	type invalid struct {
+		Id      string
+		Expires int `dynago:",unix"`
+	}
+
+	if _, err := buildItem(invalid{}); err == nil {
+		t.Errorf("buildItem() error = nil, want unix to require a time")
+	}
+
+	field := schemaField{timeEncoding: timeUnix}
+	if got, err := toAttributeType(field, timeType); err != nil || got != types.ScalarAttributeTypeN {
+		t.Errorf("toAttributeType() = %v, %v, want N", got, err)
+	}
+}
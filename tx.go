@@ -0,0 +1,341 @@
+package dynago
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Tx is a transaction of writes across any number of Tables.
+// Either all of the writes succeed, or none of them do.
+//
+//  // Move 10 coins between accounts, as long as there are enough of them
+//  err := dynago.NewTx().
+//    Update(accounts, from, dynago.Add("Coins", dynago.N(-10)), dynago.Gte("Coins", dynago.N(10))).
+//    Update(accounts, to, dynago.Add("Coins", dynago.N(10)), dynago.All()).
+//    Execute()
+//
+// When the transaction is canceled, for example because a Condition was
+// not met, a *TxCanceledError tells which operations caused it.
+//
+// All of the Tables of a Tx must be of the same Client, since a
+// transaction cannot span regions or accounts.
+type Tx struct {
+	client *Client
+	tables []*Table
+	items  []types.TransactWriteItem
+	token  *string
+	err    error
+}
+
+// NewTx creates an empty Tx with a random client request token.
+// Executing the same Tx more than once is idempotent, as long as it
+// happens within the ten minutes DynamoDb remembers the token for.
+func NewTx() Tx {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return Tx{} // DynamoDb generates a token per request instead
+	}
+
+	token := hex.EncodeToString(bytes)
+
+	return Tx{token: &token}
+}
+
+// WithToken sets the client request token of the Tx.
+func (tx Tx) WithToken(token string) Tx {
+	tx.token = &token
+
+	return tx
+}
+
+// Put puts the item into the Table when the Condition is met.
+func (tx Tx) Put(table *Table, item interface{}, condition Condition) Tx {
+	toPut, err := buildItem(item)
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	b := newExprBuilder()
+	expr := condition.buildExpr(b)
+
+	values, err := b.attributeValues()
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	return tx.with(table, types.TransactWriteItem{Put: &types.Put{
+		TableName:                           &table.Name,
+		Item:                                toPut,
+		ConditionExpression:                 expr,
+		ExpressionAttributeNames:            b.attributeNames(),
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}})
+}
+
+// Update applies the Update to the item with the given key when the
+// Condition is met. The key is the same as for Table.Get.
+func (tx Tx) Update(table *Table, key interface{}, update Update, condition Condition) Tx {
+	itemKey, err := table.key(key)
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	b := newExprBuilder()
+	updateExpr, err := update.buildExpr(b)
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	expr := condition.buildExpr(b)
+
+	values, err := b.attributeValues()
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	return tx.with(table, types.TransactWriteItem{Update: &types.Update{
+		TableName:                           &table.Name,
+		Key:                                 itemKey,
+		UpdateExpression:                    &updateExpr,
+		ConditionExpression:                 expr,
+		ExpressionAttributeNames:            b.attributeNames(),
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}})
+}
+
+// Delete deletes the item with the given key when the Condition is met.
+// The key is the same as for Table.Get.
+func (tx Tx) Delete(table *Table, key interface{}, condition Condition) Tx {
+	itemKey, err := table.key(key)
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	b := newExprBuilder()
+	expr := condition.buildExpr(b)
+
+	values, err := b.attributeValues()
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	return tx.with(table, types.TransactWriteItem{Delete: &types.Delete{
+		TableName:                           &table.Name,
+		Key:                                 itemKey,
+		ConditionExpression:                 expr,
+		ExpressionAttributeNames:            b.attributeNames(),
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}})
+}
+
+// ConditionCheck cancels the transaction unless the item with the given
+// key meets the Condition. The item itself is left untouched.
+func (tx Tx) ConditionCheck(table *Table, key interface{}, condition Condition) Tx {
+	itemKey, err := table.key(key)
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	b := newExprBuilder()
+	expr := condition.buildExpr(b)
+
+	values, err := b.attributeValues()
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	return tx.with(table, types.TransactWriteItem{ConditionCheck: &types.ConditionCheck{
+		TableName:                           &table.Name,
+		Key:                                 itemKey,
+		ConditionExpression:                 expr,
+		ExpressionAttributeNames:            b.attributeNames(),
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}})
+}
+
+func (tx Tx) with(table *Table, item types.TransactWriteItem) Tx {
+	var err error
+	if tx.client, err = txClient(tx.client, table); err != nil {
+		return tx.fail(err)
+	}
+
+	tx.tables = append(tx.tables[:len(tx.tables):len(tx.tables)], table)
+	tx.items = append(tx.items[:len(tx.items):len(tx.items)], item)
+
+	return tx
+}
+
+// fail records the first error of building an operation, which is
+// returned by Execute instead of running the transaction.
+func (tx Tx) fail(err error) Tx {
+	if tx.err == nil {
+		tx.err = err
+	}
+
+	return tx
+}
+
+// Execute runs all of the operations of the Tx in a single transaction.
+func (tx Tx) Execute() error { return tx.ExecuteCtx(dbCtx) }
+
+// ExecuteCtx is the same as Tx.Execute, running the transaction with
+// the context.
+func (tx Tx) ExecuteCtx(ctx context.Context) error {
+	if tx.err != nil {
+		return tx.err
+	}
+
+	_, err := txDB(tx.client).TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems:      tx.items,
+		ClientRequestToken: tx.token,
+	})
+
+	return txError(err, tx.tables)
+}
+
+// TransactGet reads items across any number of Tables in a single
+// transaction, so the items are never seen halfway through a Tx.
+//
+//  results, err := dynago.TransactGet{}.
+//    Get(accounts, from).
+//    Get(accounts, to).
+//    Execute()
+//
+// The same as for a Tx, all of the Tables must be of the same Client.
+type TransactGet struct {
+	client *Client
+	tables []*Table
+	items  []types.TransactGetItem
+	err    error
+}
+
+// Get reads the item with the given key from the Table.
+// The key is the same as for Table.Get.
+func (tx TransactGet) Get(table *Table, key interface{}) TransactGet {
+	itemKey, err := table.key(key)
+	if err != nil {
+		return tx.fail(err)
+	}
+
+	if tx.client, err = txClient(tx.client, table); err != nil {
+		return tx.fail(err)
+	}
+
+	tx.tables = append(tx.tables[:len(tx.tables):len(tx.tables)], table)
+	b := newExprBuilder()
+	projection := b.projection(table.Projection)
+
+	tx.items = append(tx.items[:len(tx.items):len(tx.items)], types.TransactGetItem{Get: &types.Get{
+		TableName:                &table.Name,
+		Key:                      itemKey,
+		ProjectionExpression:     projection,
+		ExpressionAttributeNames: b.attributeNames(),
+	}})
+
+	return tx
+}
+
+// fail records the first error of adding a read, which is returned by
+// Execute instead of running the transaction.
+func (tx TransactGet) fail(err error) TransactGet {
+	if tx.err == nil {
+		tx.err = err
+	}
+
+	return tx
+}
+
+// Execute reads all of the items in a single transaction.
+// The returned items are in the same order as the reads, where items
+// that do not exist are nil.
+func (tx TransactGet) Execute() ([]interface{}, error) { return tx.ExecuteCtx(dbCtx) }
+
+// ExecuteCtx is the same as TransactGet.Execute, reading the items with
+// the context.
+func (tx TransactGet) ExecuteCtx(ctx context.Context) ([]interface{}, error) {
+	if tx.err != nil {
+		return nil, tx.err
+	}
+
+	output, err := txDB(tx.client).TransactGetItems(ctx, &dynamodb.TransactGetItemsInput{
+		TransactItems: tx.items,
+	})
+
+	if err != nil {
+		return nil, txError(err, tx.tables)
+	}
+
+	results := make([]interface{}, len(output.Responses))
+	for i, response := range output.Responses {
+		if response.Item == nil {
+			continue
+		}
+
+		if results[i], err = constructItem(response.Item, tx.tables[i].Schema); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// txClient is the Client of a transaction after adding the Table to it,
+// which fails when the Table is of another Client than those before it.
+func txClient(client *Client, table *Table) (*Client, error) {
+	if client == nil || client == table.dbClient() {
+		return table.dbClient(), nil
+	}
+
+	return client, fmt.Errorf("table %s is of another Client than the tables before it in the transaction", table.Name)
+}
+
+// txDB is the DynamoDb client of a transaction, which is that of the
+// default Client when it has no Tables.
+func txDB(client *Client) API {
+	if client == nil {
+		return defaultClient.db
+	}
+
+	return client.db
+}
+
+// txError turns a canceled transaction into a *TxCanceledError,
+// decoding the items of the cancellation reasons with the schemas
+// of the Tables.
+func txError(err error, tables []*Table) error {
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return err
+	}
+
+	txErr := &TxCanceledError{Message: canceled.ErrorMessage()}
+	for i, reason := range canceled.CancellationReasons {
+		if reason.Code == nil || *reason.Code == "None" {
+			continue
+		}
+
+		txReason := TxCancellationReason{Index: i, Code: *reason.Code}
+		if reason.Message != nil {
+			txReason.Message = *reason.Message
+		}
+
+		if reason.Item != nil && i < len(tables) {
+			if txReason.Item, err = constructItem(reason.Item, tables[i].Schema); err != nil {
+				return err
+			}
+		}
+
+		txErr.Reasons = append(txErr.Reasons, txReason)
+	}
+
+	return txErr
+}
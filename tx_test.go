@@ -0,0 +1,50 @@
+package dynago
+
+import (
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"testing"
+)
+
+func Test_txError(t *testing.T) {
+	type schema struct {
+		Id   int
+		Name string
+	}
+
+	none, failed, message := "None", ReasonConditionalCheckFailed, "The conditional request failed"
+	err := txError(&types.TransactionCanceledException{CancellationReasons: []types.CancellationReason{
+		{Code: &none},
+		{Code: &failed, Message: &message, Item: testItem(map[string]interface{}{"Id": 1, "Name": "abc"})},
+	}}, []*Table{{Schema: schema{}}, {Schema: schema{}}})
+
+	var canceled *TxCanceledError
+	if !errors.As(err, &canceled) {
+		t.Fatalf("txError() = %v, want *TxCanceledError", err)
+	}
+
+	want := []TxCancellationReason{{1, failed, message, schema{1, "abc"}}}
+	if !reflect.DeepEqual(canceled.Reasons, want) {
+		t.Errorf("txError() reasons = %v, want %v", canceled.Reasons, want)
+	}
+
+	other := errors.New("other")
+	if got := txError(other, nil); got != other {
+		t.Errorf("txError() = %v, want %v", got, other)
+	}
+}
+
+func TestTx_with(t *testing.T) {
+	base := NewTx().Put(&Table{Name: "a"}, struct{ Id int }{1}, All())
+	first := base.Delete(&Table{Name: "b"}, struct{ Id int }{1}, All())
+	second := base.Delete(&Table{Name: "c"}, struct{ Id int }{1}, All())
+
+	if *first.items[1].Delete.TableName != "b" || *second.items[1].Delete.TableName != "c" {
+		t.Errorf("with() shares operations between transactions")
+	}
+
+	if *first.token != *second.token {
+		t.Errorf("with() changes the client request token")
+	}
+}
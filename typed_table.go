@@ -0,0 +1,254 @@
+package dynago
+
+import "context"
+
+// TypedTable is a Table whose items are of type T, so that results
+// need no type assertions. T is the schema of the Table and therefore
+// has to be a struct type.
+//
+//  people, err := dynago.NewTypedTable[Person]("Person")
+//  if err != nil {
+//    panic(err)
+//  }
+//
+//  swedes, err := people.Query(dynago.Eq("Country", dynago.S("Sweden")))
+//  for _, swede := range swedes {
+//    fmt.Println(swede.FirstName)
+//  }
+//
+// Items are encoded and decoded the same way as for a Table, and all
+// errors are the same as those of the matching Table operations.
+type TypedTable[T any] struct {
+	table *Table
+}
+
+// NewTypedTable creates a new TypedTable.
+// A TypedTable cannot be created if the Table does not exist in DynamoDb.
+func NewTypedTable[T any](name string) (*TypedTable[T], error) {
+	return NewTypedTableCtx[T](dbCtx, name)
+}
+
+// NewTypedTableCtx is the same as NewTypedTable, describing the table
+// with the context.
+func NewTypedTableCtx[T any](ctx context.Context, name string) (*TypedTable[T], error) {
+	var schema T
+
+	table, err := NewTableCtx(ctx, name, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedTable[T]{table}, nil
+}
+
+// CreateTypedTable creates a DynamoDb table with T as its schema, the
+// same way as CreateTable.
+func CreateTypedTable[T any](name string) (*TypedTable[T], error) {
+	return CreateTypedTableCtx[T](dbCtx, name)
+}
+
+// CreateTypedTableCtx is the same as CreateTypedTable, creating the
+// table with the context.
+func CreateTypedTableCtx[T any](ctx context.Context, name string) (*TypedTable[T], error) {
+	var schema T
+
+	table, err := CreateTableCtx(ctx, name, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedTable[T]{table}, nil
+}
+
+// TypedTableOf wraps a Table whose Schema is a T, such as one created
+// by a Client, as a TypedTable.
+//
+//  table, err := client.NewTable("Person", Person{})
+//  people := dynago.TypedTableOf[Person](table)
+func TypedTableOf[T any](table *Table) *TypedTable[T] { return &TypedTable[T]{table} }
+
+// Table is the untyped Table, such as for use in a Tx.
+func (t TypedTable[T]) Table() *Table { return t.table }
+
+// Get fetches a single item by its primary key. See Table.Get.
+func (t TypedTable[T]) Get(key T) (T, error) { return t.GetCtx(dbCtx, key) }
+
+// GetCtx is the same as Get, with the context. See Table.GetCtx.
+func (t TypedTable[T]) GetCtx(ctx context.Context, key T) (T, error) {
+	return typed[T](t.table.GetCtx(ctx, key))
+}
+
+// GetConsistent fetches a single item with a strongly consistent read.
+// See Table.GetConsistent.
+func (t TypedTable[T]) GetConsistent(key T) (T, error) { return t.GetConsistentCtx(dbCtx, key) }
+
+// GetConsistentCtx is the same as GetConsistent, with the context.
+// See Table.GetConsistentCtx.
+func (t TypedTable[T]) GetConsistentCtx(ctx context.Context, key T) (T, error) {
+	return typed[T](t.table.GetConsistentCtx(ctx, key))
+}
+
+// GetMany fetches the items for many keys at once. See Table.GetMany.
+// Keys without an item have the zero value of T as their item.
+func (t TypedTable[T]) GetMany(keys []T) ([]T, error) { return t.GetManyCtx(dbCtx, keys) }
+
+// GetManyCtx is the same as GetMany, with the context.
+// See Table.GetManyCtx.
+func (t TypedTable[T]) GetManyCtx(ctx context.Context, keys []T) ([]T, error) {
+	return typedAll[T](t.table.GetManyCtx(ctx, untypedAll(keys)))
+}
+
+// Query queries the items which meet the Condition. See Table.Query.
+func (t TypedTable[T]) Query(condition Condition) ([]T, error) {
+	return t.QueryCtx(dbCtx, condition)
+}
+
+// QueryCtx is the same as Query, with the context. See Table.QueryCtx.
+func (t TypedTable[T]) QueryCtx(ctx context.Context, condition Condition) ([]T, error) {
+	return typedAll[T](t.table.QueryCtx(ctx, condition))
+}
+
+// QueryWithFilter queries with a separate key condition and filter.
+// See Table.QueryWithFilter.
+func (t TypedTable[T]) QueryWithFilter(key Condition, filter Condition) ([]T, error) {
+	return t.QueryWithFilterCtx(dbCtx, key, filter)
+}
+
+// QueryWithFilterCtx is the same as QueryWithFilter, with the context.
+// See Table.QueryWithFilterCtx.
+func (t TypedTable[T]) QueryWithFilterCtx(ctx context.Context, key Condition, filter Condition) ([]T, error) {
+	return typedAll[T](t.table.QueryWithFilterCtx(ctx, key, filter))
+}
+
+// QueryIter queries the items with a TypedIter. See Table.QueryIter.
+func (t TypedTable[T]) QueryIter(condition Condition) *TypedIter[T] {
+	return t.QueryIterCtx(dbCtx, condition)
+}
+
+// QueryIterCtx is the same as QueryIter, with the context.
+// See Table.QueryIterCtx.
+func (t TypedTable[T]) QueryIterCtx(ctx context.Context, condition Condition) *TypedIter[T] {
+	return &TypedIter[T]{t.table.QueryIterCtx(ctx, condition)}
+}
+
+// ScanAll scans all items. See Table.ScanAll.
+func (t TypedTable[T]) ScanAll() ([]T, error) { return t.ScanAllCtx(dbCtx) }
+
+// ScanAllCtx is the same as ScanAll, with the context.
+// See Table.ScanAllCtx.
+func (t TypedTable[T]) ScanAllCtx(ctx context.Context) ([]T, error) {
+	return typedAll[T](t.table.ScanAllCtx(ctx))
+}
+
+// Scan scans the items which meet the Condition. See Table.Scan.
+func (t TypedTable[T]) Scan(condition Condition) ([]T, error) { return t.ScanCtx(dbCtx, condition) }
+
+// ScanCtx is the same as Scan, with the context. See Table.ScanCtx.
+func (t TypedTable[T]) ScanCtx(ctx context.Context, condition Condition) ([]T, error) {
+	return typedAll[T](t.table.ScanCtx(ctx, condition))
+}
+
+// ScanIter scans the items with a TypedIter. See Table.ScanIter.
+func (t TypedTable[T]) ScanIter(condition Condition) *TypedIter[T] {
+	return t.ScanIterCtx(dbCtx, condition)
+}
+
+// ScanIterCtx is the same as ScanIter, with the context.
+// See Table.ScanIterCtx.
+func (t TypedTable[T]) ScanIterCtx(ctx context.Context, condition Condition) *TypedIter[T] {
+	return &TypedIter[T]{t.table.ScanIterCtx(ctx, condition)}
+}
+
+// Put puts the item. See Table.Put.
+func (t TypedTable[T]) Put(item T) (T, error) { return t.PutCtx(dbCtx, item) }
+
+// PutCtx is the same as Put, with the context. See Table.PutCtx.
+func (t TypedTable[T]) PutCtx(ctx context.Context, item T) (T, error) {
+	return typed[T](t.table.PutCtx(ctx, item))
+}
+
+// PutWithCondition puts the item when the Condition is met.
+// See Table.PutWithCondition.
+func (t TypedTable[T]) PutWithCondition(condition Condition, item T) (T, error) {
+	return t.PutWithConditionCtx(dbCtx, condition, item)
+}
+
+// PutWithConditionCtx is the same as PutWithCondition, with the context.
+// See Table.PutWithConditionCtx.
+func (t TypedTable[T]) PutWithConditionCtx(ctx context.Context, condition Condition, item T) (T, error) {
+	return typed[T](t.table.PutWithConditionCtx(ctx, condition, item))
+}
+
+// PutAll puts many items at once. See Table.PutAll.
+func (t TypedTable[T]) PutAll(items []T) ([]T, error) { return t.PutAllCtx(dbCtx, items) }
+
+// PutAllCtx is the same as PutAll, with the context.
+// See Table.PutAllCtx.
+func (t TypedTable[T]) PutAllCtx(ctx context.Context, items []T) ([]T, error) {
+	return typedAll[T](t.table.PutAllCtx(ctx, untypedAll(items)))
+}
+
+// Update applies the Update to the item with the given key when the
+// Condition is met. See Table.Update. When the Update returns no
+// values, the returned item is the zero value of T.
+func (t TypedTable[T]) Update(key T, update Update, condition Condition) (T, error) {
+	return t.UpdateCtx(dbCtx, key, update, condition)
+}
+
+// UpdateCtx is the same as Update, with the context. See Table.UpdateCtx.
+func (t TypedTable[T]) UpdateCtx(ctx context.Context, key T, update Update, condition Condition) (T, error) {
+	return typed[T](t.table.UpdateCtx(ctx, key, update, condition))
+}
+
+// DeleteItem deletes the item with the same key. See Table.DeleteItem.
+func (t TypedTable[T]) DeleteItem(item T) (T, error) { return t.DeleteItemCtx(dbCtx, item) }
+
+// DeleteItemCtx is the same as DeleteItem, with the context.
+// See Table.DeleteItemCtx.
+func (t TypedTable[T]) DeleteItemCtx(ctx context.Context, item T) (T, error) {
+	return typed[T](t.table.DeleteItemCtx(ctx, item))
+}
+
+// Delete deletes the items which meet the Condition. See Table.Delete.
+func (t TypedTable[T]) Delete(condition Condition) ([]T, error) {
+	return t.DeleteCtx(dbCtx, condition)
+}
+
+// DeleteCtx is the same as Delete, with the context. See Table.DeleteCtx.
+func (t TypedTable[T]) DeleteCtx(ctx context.Context, condition Condition) ([]T, error) {
+	deleted, err := t.table.DeleteCtx(ctx, condition)
+	items, _ := deleted.([]interface{})
+
+	return typedAll[T](items, err)
+}
+
+// typed asserts the result of a Table operation to be a T, keeping the
+// zero value for nil results.
+func typed[T any](item interface{}, err error) (T, error) {
+	result, _ := item.(T)
+
+	return result, err
+}
+
+// typedAll is the same as typed for many results.
+func typedAll[T any](items []interface{}, err error) ([]T, error) {
+	if items == nil {
+		return nil, err
+	}
+
+	results := make([]T, len(items))
+	for i, item := range items {
+		results[i], _ = item.(T)
+	}
+
+	return results, err
+}
+
+func untypedAll[T any](items []T) []interface{} {
+	untyped := make([]interface{}, len(items))
+	for i, item := range items {
+		untyped[i] = item
+	}
+
+	return untyped
+}
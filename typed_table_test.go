@@ -0,0 +1,25 @@
+package dynago
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_typedAll(t *testing.T) {
+	type item struct{ Id int }
+
+	failed := errors.New("failed")
+	got, err := typedAll[item]([]interface{}{item{1}, nil, item{3}}, failed)
+	if err != failed {
+		t.Errorf("typedAll() error = %v, want %v", err, failed)
+	}
+
+	if want := []item{{1}, {}, {3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("typedAll() = %v, want %v", got, want)
+	}
+
+	if got, _ := typedAll[item](nil, failed); got != nil {
+		t.Errorf("typedAll() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,256 @@
+package dynago
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strings"
+)
+
+// Update describes the changes made to an item by Table.Update.
+// Just like a Condition, an Update is built by chaining actions.
+//
+//  // Happy birthday!
+//  update := dynago.Set("Age", dynago.Plus(dynago.Field("Age"), dynago.N(1))).
+//    Remove("Nickname").
+//    WithReturnValues(dynago.ReturnAllOld)
+//
+// The functions Set, Remove, Add and Delete take either a field name or
+// an AttributePath. The chained actions take only an AttributePath, as
+// methods cannot be generic, so a name held in a string variable is
+// converted first.
+//
+//  update := dynago.Set(name, dynago.S("abc")).Remove(dynago.AttributePath(other))
+type Update struct {
+	actions      []updateAction
+	returnValues ReturnValues
+}
+
+type updateAction struct {
+	actionType updateActionType
+	path       AttributePath
+	operand    Operand
+}
+
+// Set assigns the operand to the field, creating the field if needed.
+func (u Update) Set(path AttributePath, operand Operand) Update {
+	return u.with(updateAction{set, path, operand})
+}
+
+// Remove removes the field from the item.
+func (u Update) Remove(path AttributePath) Update { return u.with(updateAction{remove, path, nil}) }
+
+// Add adds the number to a numeric field, or the elements to a set field.
+// A missing field is treated as 0 or the empty set.
+func (u Update) Add(path AttributePath, value Value) Update {
+	return u.with(updateAction{add, path, value})
+}
+
+// Delete removes the elements from a set field.
+func (u Update) Delete(path AttributePath, value Value) Update {
+	return u.with(updateAction{del, path, value})
+}
+
+// WithReturnValues chooses which item Table.Update returns.
+// By default this is ReturnAllNew.
+func (u Update) WithReturnValues(returnValues ReturnValues) Update {
+	u.returnValues = returnValues
+
+	return u
+}
+
+func (u Update) with(action updateAction) Update {
+	actions := make([]updateAction, len(u.actions), len(u.actions)+1)
+	copy(actions, u.actions)
+	u.actions = append(actions, action)
+
+	return u
+}
+
+// buildExpr renders the Update with the placeholders of the exprBuilder.
+// An Update without actions, or with a nil Operand, is rejected rather
+// than sent to DynamoDb.
+func (u Update) buildExpr(b *exprBuilder) (string, error) {
+	if len(u.actions) == 0 {
+		return "", errors.New("update requires at least one action")
+	}
+
+	var sections [4][]string
+	for _, action := range u.actions {
+		expr := b.path(action.path)
+		switch action.actionType {
+		case set:
+			operand, err := renderOperand(action.operand, b)
+			if err != nil {
+				return "", fmt.Errorf("cannot set %s: %w", action.path, err)
+			}
+
+			expr += " = " + operand
+		case add, del:
+			expr += " " + b.value(action.operand.(Value).raw)
+		}
+
+		sections[action.actionType] = append(sections[action.actionType], expr)
+	}
+
+	var clauses []string
+	for actionType, exprs := range sections {
+		if len(exprs) > 0 {
+			clauses = append(clauses, updateActionType(actionType).String()+" "+strings.Join(exprs, ", "))
+		}
+	}
+
+	return strings.Join(clauses, " "), nil
+}
+
+func renderOperand(operand Operand, b *exprBuilder) (string, error) {
+	switch o := operand.(type) {
+	case nil:
+		return "", errors.New("operand is nil")
+	case Value:
+		return b.value(o.raw), nil
+	case fieldOperand:
+		return b.path(o.path), nil
+	case funcOperand:
+		var args []string
+		for _, arg := range o.args {
+			expr, err := renderOperand(arg, b)
+			if err != nil {
+				return "", err
+			}
+
+			args = append(args, expr)
+		}
+
+		return o.name + "(" + strings.Join(args, ", ") + ")", nil
+	default:
+		a := operand.(arithmeticOperand)
+		left, err := renderOperand(a.left, b)
+		if err != nil {
+			return "", err
+		}
+
+		right, err := renderOperand(a.right, b)
+		if err != nil {
+			return "", err
+		}
+
+		return left + " " + a.op + " " + right, nil
+	}
+}
+
+func Set[P pathLike](path P, operand Operand) Update {
+	return Update{}.Set(AttributePath(path), operand)
+}
+
+func Remove[P pathLike](path P) Update {
+	return Update{}.Remove(AttributePath(path))
+}
+
+func Add[P pathLike](path P, value Value) Update {
+	return Update{}.Add(AttributePath(path), value)
+}
+
+func Delete[P pathLike](path P, value Value) Update {
+	return Update{}.Delete(AttributePath(path), value)
+}
+
+type updateActionType uint8
+
+const (
+	set updateActionType = iota
+	remove
+	add
+	del
+)
+
+func (a updateActionType) String() string {
+	switch a {
+	case set:
+		return "SET"
+	case remove:
+		return "REMOVE"
+	case add:
+		return "ADD"
+	default:
+		return "DELETE"
+	}
+}
+
+// Operand is the right hand side of a Set.
+// A Value is an Operand, as are the results of Field, ListAppend,
+// IfNotExists, Plus and Minus.
+type Operand interface{ operand() }
+
+func (Value) operand() {}
+
+type fieldOperand struct{ path AttributePath }
+
+func (fieldOperand) operand() {}
+
+type funcOperand struct {
+	name string
+	args []Operand
+}
+
+func (funcOperand) operand() {}
+
+type arithmeticOperand struct {
+	op          string
+	left, right Operand
+}
+
+func (arithmeticOperand) operand() {}
+
+// Field refers to the current value of another field in the item.
+func Field[P pathLike](path P) Operand { return fieldOperand{AttributePath(path)} }
+
+// ListAppend concatenates two lists.
+//
+//  dynago.Set("Tags", dynago.ListAppend(dynago.Field("Tags"), dynago.L([]interface{}{"new"})))
+func ListAppend(list, other Operand) Operand {
+	return funcOperand{"list_append", []Operand{list, other}}
+}
+
+// IfNotExists evaluates to the field's value if it exists,
+// otherwise it evaluates to the given operand.
+func IfNotExists[P pathLike](path P, operand Operand) Operand {
+	return funcOperand{"if_not_exists", []Operand{Field(path), operand}}
+}
+
+// Plus adds two numeric operands.
+func Plus(left, right Operand) Operand { return arithmeticOperand{"+", left, right} }
+
+// Minus subtracts the right numeric operand from the left.
+func Minus(left, right Operand) Operand { return arithmeticOperand{"-", left, right} }
+
+// ReturnValues chooses the item returned from Table.Update.
+type ReturnValues uint8
+
+const (
+	// ReturnAllNew returns the whole item after the update.
+	ReturnAllNew ReturnValues = iota
+	// ReturnAllOld returns the whole item before the update.
+	ReturnAllOld
+	// ReturnUpdatedNew returns only the updated fields after the update.
+	ReturnUpdatedNew
+	// ReturnUpdatedOld returns only the updated fields before the update.
+	ReturnUpdatedOld
+	// ReturnNone returns no item at all.
+	ReturnNone
+)
+
+func (r ReturnValues) toReturnValue() types.ReturnValue {
+	switch r {
+	case ReturnAllOld:
+		return types.ReturnValueAllOld
+	case ReturnUpdatedNew:
+		return types.ReturnValueUpdatedNew
+	case ReturnUpdatedOld:
+		return types.ReturnValueUpdatedOld
+	case ReturnNone:
+		return types.ReturnValueNone
+	default:
+		return types.ReturnValueAllNew
+	}
+}
@@ -0,0 +1,112 @@
+package dynago
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdate_buildExpr(t *testing.T) {
+	name := "Name"
+
+	tests := []struct {
+		name       string
+		update     Update
+		wantExpr   string
+		wantNames  map[string]string
+		wantValues map[string]interface{}
+	}{
+		{
+			"set value",
+			Set("Age", N(3)),
+			"SET #n0 = :v0",
+			map[string]string{"#n0": "Age"},
+			map[string]interface{}{":v0": 3},
+		},
+		{
+			"increment",
+			Set("Age", Plus(Field("Age"), N(1))),
+			"SET #n0 = #n0 + :v0",
+			map[string]string{"#n0": "Age"},
+			map[string]interface{}{":v0": 1},
+		},
+		{
+			"functions",
+			Set("Tags", ListAppend(IfNotExists("Tags", L([]interface{}{})), L([]interface{}{"a"}))),
+			"SET #n0 = list_append(if_not_exists(#n0, :v0), :v1)",
+			map[string]string{"#n0": "Tags"},
+			map[string]interface{}{":v0": []interface{}{}, ":v1": []interface{}{"a"}},
+		},
+		{
+			"paths",
+			Set("Address.City", Field(Path("Cities").Index(0))).Remove(Path("Tags").Index(2)),
+			"SET #n0.#n1 = #n2[0] REMOVE #n3[2]",
+			map[string]string{"#n0": "Address", "#n1": "City", "#n2": "Cities", "#n3": "Tags"},
+			map[string]interface{}{},
+		},
+		{
+			"string variables",
+			Set(name, Field(name)).Remove(AttributePath(name)),
+			"SET #n0 = #n0 REMOVE #n0",
+			map[string]string{"#n0": "Name"},
+			map[string]interface{}{},
+		},
+		{
+			"all actions",
+			Remove("Nickname").
+				Delete("Colors", SS([]string{"red"})).
+				Add("Visits", N(1)).
+				Set("Name", S("abc")).
+				Set("Age", Minus(Field("Age"), N(1))),
+			"SET #n3 = :v2, #n4 = #n4 - :v3 REMOVE #n0 ADD #n2 :v1 DELETE #n1 :v0",
+			map[string]string{"#n0": "Nickname", "#n1": "Colors", "#n2": "Visits", "#n3": "Name", "#n4": "Age"},
+			map[string]interface{}{":v0": StringSet{"red"}, ":v1": 1, ":v2": "abc", ":v3": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newExprBuilder()
+			if got, err := tt.update.buildExpr(b); err != nil || got != tt.wantExpr {
+				t.Errorf("buildExpr() = %v, %v, want %v", got, err, tt.wantExpr)
+			}
+			if !reflect.DeepEqual(b.names, tt.wantNames) {
+				t.Errorf("buildExpr() names = %v, want %v", b.names, tt.wantNames)
+			}
+			if !reflect.DeepEqual(b.values, tt.wantValues) {
+				t.Errorf("buildExpr() values = %v, want %v", b.values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestUpdate_buildExpr_invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		update Update
+	}{
+		{"no actions", Update{}},
+		{"only return values", Update{}.WithReturnValues(ReturnAllOld)},
+		{"nil operand", Set("Age", nil)},
+		{"nil argument", Set("Tags", ListAppend(Field("Tags"), nil))},
+		{"nil term", Remove("Nickname").Set("Age", Plus(nil, N(1)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := tt.update.buildExpr(newExprBuilder()); err == nil {
+				t.Errorf("buildExpr() = %v, want an error", got)
+			}
+		})
+	}
+}
+
+func TestTable_Update_invalid(t *testing.T) {
+	// The API is nil, so any request made panics
+	table := NewClientWithAPI(struct{ API }{}).newTable("Test", struct{ Id int }{}, nil)
+
+	if _, err := table.Update(struct{ Id int }{1}, Update{}, All()); err == nil {
+		t.Errorf("Update() err = nil, want an error for an empty update")
+	}
+
+	if err := NewTx().Update(table, struct{ Id int }{1}, Set("Age", nil), All()).Execute(); err == nil {
+		t.Errorf("Tx.Update() err = nil, want an error for a nil operand")
+	}
+}